@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/upsun/whatsun/pkg/osv"
+)
+
+type vulnerabilityInfo struct {
+	dependencyInfo
+	Match osv.Match
+}
+
+// auditDependencies matches dependencies against an OSV database in a local directory. Dependencies without a
+// resolved version, or without an OSV ecosystem, cannot be checked.
+func auditDependencies(deps []dependencyInfo, dbDir string, stderr io.Writer) ([]vulnerabilityInfo, error) {
+	note := noter(stderr)
+
+	db, err := osv.Load(os.DirFS(dbDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load advisories from %s: %w", dbDir, err)
+	}
+	note("Loaded advisories for %d packages from: %s", db.Len(), dbDir)
+
+	var vulns []vulnerabilityInfo
+	var unchecked int
+	for _, info := range deps {
+		ecosystem := osv.Ecosystem(info.Manager, info.Dependency)
+		if ecosystem == "" || info.Dependency.Version == "" {
+			unchecked++
+			continue
+		}
+		for _, m := range db.Query(ecosystem, info.Dependency.Name, info.Dependency.Version) {
+			vulns = append(vulns, vulnerabilityInfo{dependencyInfo: info, Match: m})
+		}
+	}
+	if unchecked > 0 {
+		note("Skipped %d dependencies without a resolved version or a supported ecosystem", unchecked)
+	}
+	return vulns, nil
+}
+
+// outputAudit prints vulnerabilities as a table or as plain tab-separated values. An error is returned if any
+// vulnerabilities were found, so that the command exits with a non-zero status.
+func outputAudit(vulns []vulnerabilityInfo, plain bool, stdout, stderr io.Writer) error {
+	if len(vulns) == 0 {
+		fmt.Fprintln(stderr, "No known vulnerabilities found.")
+		return nil
+	}
+
+	if plain {
+		fmt.Fprintln(stdout, "Path\tTool\tName\tVersion\tID\tSeverity\tFixed")
+		for _, v := range vulns {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				v.Path,
+				v.Dependency.ToolName,
+				v.Dependency.Name,
+				v.Dependency.Version,
+				v.Match.Advisory.ID,
+				v.Match.Severity,
+				strings.Join(v.Match.Fixed, ", "),
+			)
+		}
+	} else {
+		tbl := table.NewWriter()
+		tbl.SetOutputMirror(stdout)
+		tbl.AppendHeader(table.Row{"Path", "Tool", "Name", "Version", "ID", "Severity", "Fixed"})
+		tbl.SetAllowedRowLength(getTerminalWidth())
+		for _, v := range vulns {
+			tbl.AppendRow(table.Row{
+				v.Path,
+				v.Dependency.ToolName,
+				v.Dependency.Name,
+				v.Dependency.Version,
+				v.Match.Advisory.ID,
+				v.Match.Severity,
+				strings.Join(v.Match.Fixed, ", "),
+			})
+		}
+		tbl.Render()
+	}
+
+	if len(vulns) == 1 {
+		return fmt.Errorf("found 1 vulnerability")
+	}
+	return fmt.Errorf("found %d vulnerabilities", len(vulns))
+}
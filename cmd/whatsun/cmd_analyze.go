@@ -12,6 +12,7 @@ import (
 
 	"github.com/upsun/whatsun"
 	"github.com/upsun/whatsun/pkg/rules"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 func analyzeCmd() *cobra.Command {
@@ -69,10 +70,12 @@ func runAnalyze(ctx context.Context, path string, ignore []string, plain bool, s
 		return err
 	}
 
-	reports, err := analyzer.Analyze(ctx, fsys, ".")
+	sess := session.New(fsys)
+	reports, err := analyzer.AnalyzeSession(ctx, sess, ".")
 	if err != nil {
 		return fmt.Errorf("analysis failed: %v", err)
 	}
+	warnDiagnostics(sess.Diagnostics(), stderr)
 
 	if len(reports) == 0 {
 		fmt.Fprintln(stderr, "No results found.")
@@ -10,19 +10,34 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/spf13/cobra"
 
 	"github.com/upsun/whatsun/internal/fsgitignore"
 	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/license"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
+type depsOptions struct {
+	ignore          []string
+	includeIndirect bool
+	includeDev      bool
+	plain           bool
+	format          string
+	tree            bool
+	why             string
+	audit           string
+	conflicts       bool
+	platform        bool
+	diff            string
+	categories      []string
+}
+
 func depsCmd() *cobra.Command {
-	var ignore []string
-	var includeIndirect bool
-	var includeDev bool
-	var plain bool
+	var opts depsOptions
 	cmd := &cobra.Command{
 		Use:   "deps [path]",
 		Short: "List dependencies found in the repository",
@@ -37,54 +52,185 @@ func depsCmd() *cobra.Command {
 			if len(args) > 0 {
 				path = args[0]
 			}
-			return runDeps(cmd.Context(), path, ignore, includeIndirect, includeDev, plain, cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			return runDeps(cmd.Context(), path, opts, cmd.OutOrStdout(), cmd.ErrOrStderr())
 		},
 	}
-	cmd.Flags().StringSliceVar(&ignore, "ignore", []string{},
+	cmd.Flags().StringSliceVar(&opts.ignore, "ignore", []string{},
 		"Paths (or patterns) to ignore, adding to defaults.")
-	cmd.Flags().BoolVar(&includeIndirect, "include-indirect", false,
+	cmd.Flags().BoolVar(&opts.includeIndirect, "include-indirect", false,
 		"Include indirect/transitive dependencies in addition to direct dependencies.")
-	cmd.Flags().BoolVar(&includeDev, "include-dev", false,
+	cmd.Flags().BoolVar(&opts.includeDev, "include-dev", false,
 		"Include development-only dependencies.")
-	cmd.Flags().BoolVar(&plain, "plain", false,
+	cmd.Flags().BoolVar(&opts.plain, "plain", false,
 		"Output plain tab-separated values with header row.")
+	cmd.Flags().StringVar(&opts.format, "format", formatTable,
+		"Output format: "+strings.Join(depsFormats, ", ")+
+			". SBOM formats include the same dependencies as the table, so use --include-indirect and --include-dev "+
+			"for a complete SBOM.")
+	cmd.Flags().BoolVar(&opts.tree, "tree", false,
+		"Output the dependency tree from lock files.")
+	cmd.Flags().StringVar(&opts.why, "why", "",
+		"Explain why a package is installed, showing the paths to it from direct dependencies.")
+	cmd.Flags().StringVar(&opts.audit, "audit", "",
+		"Check dependencies against OSV advisories in a local directory (e.g. an extracted OSV data dump). "+
+			"Only the listed dependencies are checked, so use --include-indirect to check transitive dependencies.")
+	cmd.Flags().BoolVar(&opts.conflicts, "conflicts", false,
+		"Report dependencies whose constraints, versions or tools differ between directories. "+
+			"Only the listed dependencies are compared, so use --include-indirect to compare transitive dependencies.")
+	cmd.Flags().BoolVar(&opts.platform, "platform", false,
+		"List platform requirements instead of packages, e.g. the PHP version and extensions in composer.json.")
+	cmd.Flags().StringVar(&opts.diff, "diff", "",
+		"Compare dependencies between two revisions of a local Git repository, e.g. \"main..HEAD\" "+
+			"(an empty revision means HEAD).")
+	cmd.Flags().StringSliceVar(&opts.categories, "category", []string{},
+		"Only list dependencies in these categories, e.g. \"framework\", \"database\", \"testing\", \"linting\", "+
+			"\"build\", \"types\" or \"monitoring\".")
 
 	return cmd
 }
 
-func runDeps(
-	ctx context.Context,
-	path string,
-	ignore []string,
-	includeIndirect, includeDev, plain bool,
-	stdout, stderr io.Writer,
-) error {
+// modes returns the names of the flags set to select an output mode, other than listing dependencies.
+func (opts *depsOptions) modes() []string {
+	var modes []string
+	for _, m := range []struct {
+		name string
+		set  bool
+	}{
+		{"tree", opts.tree},
+		{"why", opts.why != ""},
+		{"audit", opts.audit != ""},
+		{"conflicts", opts.conflicts},
+		{"platform", opts.platform},
+		{"diff", opts.diff != ""},
+	} {
+		if m.set {
+			modes = append(modes, m.name)
+		}
+	}
+	return modes
+}
+
+// validate checks the combination of mode and format flags, and applies --plain, which is the same as
+// "--format plain".
+func (opts *depsOptions) validate() error {
+	if !slices.Contains(depsFormats, opts.format) {
+		return fmt.Errorf("invalid format %q (must be one of: %s)", opts.format, strings.Join(depsFormats, ", "))
+	}
+	formatFlag := "--format " + opts.format
+	if opts.plain {
+		if opts.format != formatTable && opts.format != formatPlain {
+			return fmt.Errorf("--plain cannot be used with %s", formatFlag)
+		}
+		opts.format, formatFlag = formatPlain, "--plain"
+	}
+
+	modes := opts.modes()
+	switch {
+	case len(modes) > 1:
+		return fmt.Errorf("only one of --%s can be used at a time", strings.Join(modes, ", --"))
+	case len(modes) == 0:
+		return nil
+	}
+	switch mode := modes[0]; {
+	case (mode == "tree" || mode == "why") && opts.format != formatTable:
+		return fmt.Errorf("--%s cannot be used with %s", mode, formatFlag)
+	case opts.format != formatTable && opts.format != formatPlain:
+		return fmt.Errorf("--%s cannot be used with %s (only %s or %s)", mode, formatFlag, formatTable, formatPlain)
+	}
+	return nil
+}
+
+func runDeps(ctx context.Context, path string, opts depsOptions, stdout, stderr io.Writer) error {
+	if opts.diff != "" {
+		return runDepsDiff(ctx, path, opts, stdout, stderr)
+	}
+
 	fsys, disableGitIgnore, err := setupFileSystem(ctx, path, stderr)
 	if err != nil {
 		return err
 	}
 
-	dependencies, err := collectAllDependencies(ctx, fsys, ignore, disableGitIgnore)
+	// The session caches the dependency managers, so that they are only initialized once for the listing and graphs.
+	sess := session.New(fsys)
+
+	if opts.tree || opts.why != "" {
+		graphs, err := collectGraphs(ctx, sess, opts.ignore, disableGitIgnore)
+		if err != nil {
+			return fmt.Errorf("failed to collect dependency graphs: %w", err)
+		}
+		warnDiagnostics(sess.Diagnostics(), stderr)
+		if opts.why != "" {
+			outputWhy(graphs, opts.why, stdout, stderr)
+		} else {
+			outputTree(graphs, opts.includeDev, stdout, stderr)
+		}
+		return nil
+	}
+
+	if opts.platform {
+		requirements, err := collectPlatform(ctx, sess, opts.ignore, disableGitIgnore)
+		if err != nil {
+			return fmt.Errorf("failed to collect platform requirements: %w", err)
+		}
+		warnDiagnostics(sess.Diagnostics(), stderr)
+		var filtered []dependencyInfo
+		for _, r := range requirements {
+			if opts.includeDev || !r.Dependency.IsDevOnly {
+				filtered = append(filtered, r)
+			}
+		}
+		outputPlatform(filtered, opts.format == formatPlain, stdout, stderr)
+		return nil
+	}
+
+	dependencies, err := collectAllDependencies(ctx, sess, opts.ignore, disableGitIgnore)
 	if err != nil {
 		return fmt.Errorf("failed to collect dependencies: %w", err)
 	}
+	warnDiagnostics(sess.Diagnostics(), stderr)
 
-	// Filter dependencies based on flags
-	var filteredDeps []dependencyInfo
-	for _, depInfo := range dependencies {
-		// Skip indirect dependencies if not requested
-		if !includeIndirect && !depInfo.Dependency.IsDirect {
-			continue
+	filteredDeps := filterDependencies(dependencies, opts)
+
+	if opts.audit != "" {
+		vulns, err := auditDependencies(filteredDeps, opts.audit, stderr)
+		if err != nil {
+			return err
 		}
-		// Skip dev dependencies unless --include-dev is specified
-		if !includeDev && depInfo.Dependency.IsDevOnly {
-			continue
+		return outputAudit(vulns, opts.format == formatPlain, stdout, stderr)
+	}
+
+	if opts.conflicts {
+		outputConflicts(findConflicts(filteredDeps), opts.format == formatPlain, stdout, stderr)
+		return nil
+	}
+
+	if opts.format == formatCycloneDXJSON || opts.format == formatSPDXJSON {
+		graphs, err := collectGraphs(ctx, sess, opts.ignore, disableGitIgnore)
+		if err != nil {
+			return fmt.Errorf("failed to collect dependency graphs: %w", err)
 		}
-		filteredDeps = append(filteredDeps, depInfo)
+		projectLicense, err := license.ProjectSession(sess, ".")
+		if err != nil {
+			return fmt.Errorf("failed to detect the project license: %w", err)
+		}
+		s := newSBOM(sbomName(path), projectLicense, filteredDeps, graphs)
+		if opts.format == formatSPDXJSON {
+			return outputSPDX(s, stdout)
+		}
+		return outputCycloneDX(s, stdout)
+	}
+
+	if projectLicense, err := license.ProjectSession(sess, "."); err != nil {
+		return fmt.Errorf("failed to detect the project license: %w", err)
+	} else if projectLicense != "" {
+		noter(stderr)("Project license: %s", projectLicense)
 	}
 
 	if len(filteredDeps) == 0 {
-		if !includeIndirect {
+		if !opts.includeIndirect {
 			fmt.Fprintln(stderr, "No direct dependencies found.")
 		} else {
 			fmt.Fprintln(stderr, "No dependencies found.")
@@ -92,12 +238,12 @@ func runDeps(
 		return nil
 	}
 
-	if plain {
+	if opts.format == formatPlain {
 		outputDepsPlain(filteredDeps, stdout)
 	} else {
 		tbl := table.NewWriter()
 		tbl.SetOutputMirror(stdout)
-		tbl.AppendHeader(table.Row{"Path", "Tool", "Name", "Constraint", "Version"})
+		tbl.AppendHeader(table.Row{"Path", "Tool", "Name", "Constraint", "Version", "License", "Category"})
 
 		// Set table width to terminal width with fallback to 80
 		tbl.SetAllowedRowLength(getTerminalWidth())
@@ -109,6 +255,8 @@ func runDeps(
 				depInfo.Dependency.Name,
 				depInfo.Dependency.Constraint,
 				depInfo.Dependency.Version,
+				depInfo.Dependency.License,
+				depInfo.Dependency.Category,
 			})
 		}
 
@@ -117,6 +265,27 @@ func runDeps(
 	return nil
 }
 
+// filterDependencies filters dependencies based on flags.
+func filterDependencies(dependencies []dependencyInfo, opts depsOptions) []dependencyInfo {
+	var filteredDeps []dependencyInfo
+	for _, depInfo := range dependencies {
+		// Skip indirect dependencies if not requested
+		if !opts.includeIndirect && !depInfo.Dependency.IsDirect {
+			continue
+		}
+		// Skip dev dependencies unless --include-dev is specified
+		if !opts.includeDev && depInfo.Dependency.IsDevOnly {
+			continue
+		}
+		// Skip dependencies in other categories if --category is specified
+		if len(opts.categories) > 0 && !slices.Contains(opts.categories, depInfo.Dependency.Category) {
+			continue
+		}
+		filteredDeps = append(filteredDeps, depInfo)
+	}
+	return filteredDeps
+}
+
 type dependencyInfo struct {
 	Path       string
 	Manager    string
@@ -125,18 +294,58 @@ type dependencyInfo struct {
 
 func collectAllDependencies(
 	ctx context.Context,
-	fsys fs.FS,
+	sess *session.Session,
 	ignore []string,
 	disableGitIgnore bool,
 ) ([]dependencyInfo, error) {
 	var allDeps []dependencyInfo
+	err := walkManagers(ctx, sess, ignore, disableGitIgnore,
+		func(path, managerType string, manager dep.Manager) error {
+			// Get all dependencies by using a wildcard pattern
+			deps := manager.Find("*")
+			dep.Categorize(managerType, deps)
+			slices.SortStableFunc(deps, func(a, b dep.Dependency) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+			for _, dependency := range deps {
+				allDeps = append(allDeps, dependencyInfo{
+					Path:       path,
+					Manager:    managerType,
+					Dependency: dependency,
+				})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
 
+	return allDeps, nil
+}
+
+// warnDiagnostics prints warnings about problems found in dependency files, such as syntax errors.
+func warnDiagnostics(diagnostics []dep.Diagnostic, stderr io.Writer) {
+	for _, d := range diagnostics {
+		fmt.Fprintln(stderr, color.YellowString("Warning: %s", d.Error()))
+	}
+}
+
+// walkManagers walks the directories in the session's file system, calling fn with each initialized dependency
+// manager. The managers are cached in the session, which also lists their diagnostics.
+func walkManagers(
+	ctx context.Context,
+	sess *session.Session,
+	ignore []string,
+	disableGitIgnore bool,
+	fn func(path, managerType string, manager dep.Manager) error,
+) error {
+	fsys := sess.FS()
 	var ignorePatterns = fsgitignore.GetDefaultIgnorePatterns()
 	if len(ignore) > 0 {
 		ignorePatterns = append(ignorePatterns, fsgitignore.ParsePatterns(ignore, fsgitignore.Split("."))...)
 	}
 
-	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -176,36 +385,17 @@ func collectAllDependencies(
 		}
 
 		// Try each manager type for this directory
-		for _, managerType := range dep.AllManagerTypes {
-			manager, err := dep.GetManager(managerType, fsys, path)
+		for _, managerType := range dep.ManagerTypes() {
+			manager, err := sess.Manager(managerType, path)
 			if err != nil {
 				return err
 			}
 
-			if err := manager.Init(); err != nil {
+			if err := fn(path, managerType, manager); err != nil {
 				return err
 			}
-
-			// Get all dependencies by using a wildcard pattern
-			deps := manager.Find("*")
-			slices.SortStableFunc(deps, func(a, b dep.Dependency) int {
-				return strings.Compare(a.Name, b.Name)
-			})
-			for _, dependency := range deps {
-				allDeps = append(allDeps, dependencyInfo{
-					Path:       path,
-					Manager:    managerType,
-					Dependency: dependency,
-				})
-			}
 		}
 
 		return nil
 	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return allDeps, nil
 }
@@ -58,6 +58,7 @@ func runDigest(ctx context.Context, path string, ignore []string, useYAML bool,
 	if err != nil {
 		return err
 	}
+	warnDiagnostics(d.Diagnostics, stderr)
 	if useYAML {
 		return yaml.NewEncoder(stdout).Encode(d)
 	}
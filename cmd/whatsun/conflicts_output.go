@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+// findConflicts finds dependencies that are used inconsistently across directories.
+func findConflicts(deps []dependencyInfo) []dep.Conflict {
+	usages := make([]dep.Usage, 0, len(deps))
+	for _, info := range deps {
+		usages = append(usages, dep.Usage{Path: info.Path, ManagerType: info.Manager, Dependency: info.Dependency})
+	}
+	return dep.FindConflicts(usages)
+}
+
+// outputConflicts prints conflicts as a table or as plain tab-separated values, with a row for each usage.
+func outputConflicts(conflicts []dep.Conflict, plain bool, stdout, stderr io.Writer) {
+	if len(conflicts) == 0 {
+		fmt.Fprintln(stderr, "No dependency conflicts found.")
+		return
+	}
+
+	if plain {
+		fmt.Fprintln(stdout, "Name\tConflict\tPath\tTool\tConstraint\tVersion")
+		for _, c := range conflicts {
+			for _, u := range c.Usages {
+				fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					c.Name,
+					conflictKinds(c),
+					u.Path,
+					u.Dependency.ToolName,
+					u.Dependency.Constraint,
+					u.Dependency.Version,
+				)
+			}
+		}
+		return
+	}
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(stdout)
+	tbl.AppendHeader(table.Row{"Name", "Conflict", "Path", "Tool", "Constraint", "Version"})
+	tbl.SetAllowedRowLength(getTerminalWidth())
+	for i, c := range conflicts {
+		if i > 0 {
+			tbl.AppendSeparator()
+		}
+		for _, u := range c.Usages {
+			tbl.AppendRow(table.Row{
+				c.Name,
+				conflictKinds(c),
+				u.Path,
+				u.Dependency.ToolName,
+				u.Dependency.Constraint,
+				u.Dependency.Version,
+			})
+		}
+	}
+	tbl.Render()
+}
+
+func conflictKinds(c dep.Conflict) string {
+	kinds := make([]string, len(c.Kinds))
+	for i, k := range c.Kinds {
+		kinds[i] = string(k)
+	}
+	return strings.Join(kinds, ", ")
+}
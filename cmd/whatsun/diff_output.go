@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/files"
+	"github.com/upsun/whatsun/pkg/session"
+)
+
+// runDepsDiff compares the dependencies in two revisions of a local Git repository, given as "<rev-a>..<rev-b>".
+func runDepsDiff(ctx context.Context, path string, opts depsOptions, stdout, stderr io.Writer) error {
+	if !files.IsLocal(path) {
+		return fmt.Errorf("--diff requires a local Git repository")
+	}
+	revA, revB, ok := strings.Cut(opts.diff, "..")
+	if !ok || strings.HasPrefix(revB, ".") {
+		return fmt.Errorf("invalid revision range %q (must be in the form <rev-a>..<rev-b>)", opts.diff)
+	}
+	if revA == "" {
+		revA = "HEAD"
+	}
+	if revB == "" {
+		revB = "HEAD"
+	}
+	noter(stderr)("Comparing dependencies between %s and %s in: %s", revA, revB, path)
+
+	before, err := collectRevisionDependencies(ctx, path, revA, opts, stderr)
+	if err != nil {
+		return err
+	}
+	after, err := collectRevisionDependencies(ctx, path, revB, opts, stderr)
+	if err != nil {
+		return err
+	}
+
+	outputDiff(dep.DiffDependencies(before, after), opts.format == formatPlain, stdout, stderr)
+	return nil
+}
+
+// collectRevisionDependencies collects the dependencies in a revision, filtered based on flags.
+func collectRevisionDependencies(
+	ctx context.Context,
+	path, revision string,
+	opts depsOptions,
+	stderr io.Writer,
+) ([]dep.Usage, error) {
+	fsys, err := files.RevisionFS(path, revision)
+	if err != nil {
+		return nil, err
+	}
+	sess := session.New(fsys)
+	dependencies, err := collectAllDependencies(ctx, sess, opts.ignore, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect dependencies in %s: %w", revision, err)
+	}
+	diagnostics := sess.Diagnostics()
+	for i := range diagnostics {
+		diagnostics[i].File = revision + ":" + diagnostics[i].File
+	}
+	warnDiagnostics(diagnostics, stderr)
+
+	var usages []dep.Usage
+	for _, info := range filterDependencies(dependencies, opts) {
+		usages = append(usages, dep.Usage{Path: info.Path, ManagerType: info.Manager, Dependency: info.Dependency})
+	}
+	return usages, nil
+}
+
+// outputDiff prints dependency changes as a table or as plain tab-separated values.
+func outputDiff(changes []dep.Change, plain bool, stdout, stderr io.Writer) {
+	if len(changes) == 0 {
+		fmt.Fprintln(stderr, "No dependency changes found.")
+		return
+	}
+
+	if plain {
+		fmt.Fprintln(stdout, "Path\tTool\tName\tChange\tBefore\tAfter")
+		for _, c := range changes {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				c.Path,
+				changeTool(c),
+				c.Name,
+				c.Kind,
+				describeVersion(c.Before),
+				describeVersion(c.After),
+			)
+		}
+		return
+	}
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(stdout)
+	tbl.AppendHeader(table.Row{"Path", "Tool", "Name", "Change", "Before", "After"})
+	tbl.SetAllowedRowLength(getTerminalWidth())
+	for _, c := range changes {
+		tbl.AppendRow(table.Row{
+			c.Path,
+			changeTool(c),
+			c.Name,
+			c.Kind,
+			describeVersion(c.Before),
+			describeVersion(c.After),
+		})
+	}
+	tbl.Render()
+}
+
+func changeTool(c dep.Change) string {
+	if c.After.ToolName != "" {
+		return c.After.ToolName
+	}
+	return c.Before.ToolName
+}
+
+// describeVersion returns the resolved version of a dependency followed by its constraint, e.g. "1.2.3 (^1.2)".
+func describeVersion(d dep.Dependency) string {
+	switch {
+	case d.Version == "":
+		return d.Constraint
+	case d.Constraint == "" || d.Constraint == d.Version:
+		return d.Version
+	default:
+		return fmt.Sprintf("%s (%s)", d.Version, d.Constraint)
+	}
+}
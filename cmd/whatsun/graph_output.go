@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/session"
+)
+
+type graphInfo struct {
+	Path    string
+	Manager string
+	Graph   *dep.Graph
+}
+
+// collectGraphs finds the dependency graphs of all managers that support them, e.g. those with lock files.
+func collectGraphs(
+	ctx context.Context, sess *session.Session, ignore []string, disableGitIgnore bool,
+) ([]graphInfo, error) {
+	var graphs []graphInfo
+	err := walkManagers(ctx, sess, ignore, disableGitIgnore,
+		func(path, managerType string, manager dep.Manager) error {
+			gm, ok := manager.(dep.GraphManager)
+			if !ok {
+				return nil
+			}
+			if g := gm.Graph(); g != nil {
+				graphs = append(graphs, graphInfo{Path: path, Manager: managerType, Graph: g})
+			}
+			return nil
+		})
+	return graphs, err
+}
+
+// graphLabel returns the path and tool name of a graph, e.g. "frontend (npm)".
+func graphLabel(info graphInfo) string {
+	tool := info.Manager
+	if nodes := info.Graph.Nodes(); len(nodes) > 0 && nodes[0].ToolName != "" {
+		tool = nodes[0].ToolName
+	}
+	return fmt.Sprintf("%s (%s)", info.Path, tool)
+}
+
+func formatNode(d dep.Dependency) string {
+	if d.Version == "" {
+		return d.Name
+	}
+	return d.Name + " " + d.Version
+}
+
+// outputTree prints the dependency tree of each graph. Subtrees that were already printed are marked with "(*)".
+func outputTree(graphs []graphInfo, includeDev bool, stdout, stderr io.Writer) {
+	if len(graphs) == 0 {
+		fmt.Fprintln(stderr, "No dependency graphs found (a supported lock file is required).")
+		return
+	}
+	for i, info := range graphs {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintln(stdout, graphLabel(info))
+		var roots []dep.Dependency
+		for _, r := range info.Graph.Roots() {
+			if includeDev || !r.IsDevOnly {
+				roots = append(roots, r)
+			}
+		}
+		printed := make(map[string]bool)
+		var printNodes func(nodes []dep.Dependency, prefix string)
+		printNodes = func(nodes []dep.Dependency, prefix string) {
+			for j, d := range nodes {
+				branch, indent := "├── ", "│   "
+				if j == len(nodes)-1 {
+					branch, indent = "└── ", "    "
+				}
+				children := info.Graph.Dependencies(d.Name)
+				if printed[d.Name] && len(children) > 0 {
+					fmt.Fprintln(stdout, prefix+branch+formatNode(d)+" (*)")
+					continue
+				}
+				printed[d.Name] = true
+				fmt.Fprintln(stdout, prefix+branch+formatNode(d))
+				printNodes(children, prefix+indent)
+			}
+		}
+		printNodes(roots, "")
+	}
+}
+
+// outputWhy prints the paths from direct dependencies to a package, in each graph that contains it.
+func outputWhy(graphs []graphInfo, name string, stdout, stderr io.Writer) {
+	var found bool
+	for _, info := range graphs {
+		for _, path := range info.Graph.Why(name) {
+			found = true
+			parts := make([]string, len(path))
+			for i, d := range path {
+				parts[i] = formatNode(d)
+			}
+			fmt.Fprintf(stdout, "%s: %s\n", graphLabel(info), strings.Join(parts, " > "))
+		}
+	}
+	if !found {
+		fmt.Fprintf(stderr, "No path to %q found in dependency graphs.\n", name)
+	}
+}
@@ -10,14 +10,16 @@ import (
 
 // outputDepsPlain outputs dependencies in plain tab-separated format
 func outputDepsPlain(deps []dependencyInfo, stdout io.Writer) {
-	fmt.Fprintln(stdout, "Path\tTool\tName\tConstraint\tVersion")
+	fmt.Fprintln(stdout, "Path\tTool\tName\tConstraint\tVersion\tLicense\tCategory")
 	for _, depInfo := range deps {
-		fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			depInfo.Path,
 			depInfo.Dependency.ToolName,
 			depInfo.Dependency.Name,
 			depInfo.Dependency.Constraint,
 			depInfo.Dependency.Version,
+			depInfo.Dependency.License,
+			depInfo.Dependency.Category,
 		)
 	}
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/session"
+)
+
+// collectPlatform finds the platform requirements of all managers that declare them, such as the PHP version and
+// extensions in composer.json.
+func collectPlatform(
+	ctx context.Context, sess *session.Session, ignore []string, disableGitIgnore bool,
+) ([]dependencyInfo, error) {
+	var requirements []dependencyInfo
+	err := walkManagers(ctx, sess, ignore, disableGitIgnore,
+		func(path, managerType string, manager dep.Manager) error {
+			pm, ok := manager.(dep.PlatformManager)
+			if !ok {
+				return nil
+			}
+			platform := pm.Platform()
+			slices.SortStableFunc(platform, func(a, b dep.Dependency) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+			for _, d := range platform {
+				requirements = append(requirements, dependencyInfo{Path: path, Manager: managerType, Dependency: d})
+			}
+			return nil
+		})
+	return requirements, err
+}
+
+// outputPlatform prints platform requirements as a table or as plain tab-separated values.
+func outputPlatform(requirements []dependencyInfo, plain bool, stdout, stderr io.Writer) {
+	if len(requirements) == 0 {
+		fmt.Fprintln(stderr, "No platform requirements found.")
+		return
+	}
+
+	if plain {
+		fmt.Fprintln(stdout, "Path\tTool\tName\tConstraint")
+		for _, r := range requirements {
+			fmt.Fprintf(stdout, "%s\t%s\t%s\t%s\n",
+				r.Path,
+				r.Dependency.ToolName,
+				r.Dependency.Name,
+				r.Dependency.Constraint,
+			)
+		}
+		return
+	}
+
+	tbl := table.NewWriter()
+	tbl.SetOutputMirror(stdout)
+	tbl.AppendHeader(table.Row{"Path", "Tool", "Name", "Constraint"})
+	tbl.SetAllowedRowLength(getTerminalWidth())
+	for _, r := range requirements {
+		tbl.AppendRow(table.Row{r.Path, r.Dependency.ToolName, r.Dependency.Name, r.Dependency.Constraint})
+	}
+	tbl.Render()
+}
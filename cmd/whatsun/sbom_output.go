@@ -0,0 +1,403 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/license"
+)
+
+const (
+	formatTable         = "table"
+	formatPlain         = "plain"
+	formatCycloneDXJSON = "cyclonedx-json"
+	formatSPDXJSON      = "spdx-json"
+)
+
+var depsFormats = []string{formatTable, formatPlain, formatCycloneDXJSON, formatSPDXJSON}
+
+// sbom holds the data shared by the SBOM formats.
+type sbom struct {
+	name        string // The name of the project (the root component)
+	license     string // The license of the project, if known
+	created     time.Time
+	deps        []dependencyInfo
+	refs        []string            // A unique reference for each dependency
+	dependsOn   map[string][]string // References to the dependencies of each dependency, from lock files
+	toolVersion string
+}
+
+func newSBOM(name, projectLicense string, deps []dependencyInfo, graphs []graphInfo) *sbom {
+	s := &sbom{
+		name:        name,
+		license:     projectLicense,
+		created:     time.Now().UTC(),
+		deps:        deps,
+		refs:        make([]string, len(deps)),
+		dependsOn:   make(map[string][]string),
+		toolVersion: toolVersion(),
+	}
+	included := make(map[string]bool, len(deps))
+	for i, d := range deps {
+		s.refs[i] = uniqueRef(dependencyRef(d.Path, d.Manager, d.Dependency.Name), d.Dependency.Version, included)
+		included[s.refs[i]] = true
+	}
+	for _, g := range graphs {
+		for _, node := range g.Graph.Nodes() {
+			from := dependencyRef(g.Path, g.Manager, node.Name)
+			if !included[from] {
+				continue
+			}
+			for _, child := range g.Graph.Dependencies(node.Name) {
+				if to := dependencyRef(g.Path, g.Manager, child.Name); included[to] {
+					s.dependsOn[from] = append(s.dependsOn[from], to)
+				}
+			}
+		}
+	}
+	return s
+}
+
+func dependencyRef(path, managerType, name string) string {
+	return path + ":" + managerType + ":" + name
+}
+
+// uniqueRef returns a reference that is not yet used. A manager may list the same name more than once, e.g. a Maven
+// artifact with a classifier, so the version or else a number is added to the reference of later entries. Edges in
+// dependency graphs, which are keyed by name, then refer to the first entry.
+func uniqueRef(ref, version string, used map[string]bool) string {
+	if !used[ref] {
+		return ref
+	}
+	if version != "" && !used[ref+"@"+version] {
+		return ref + "@" + version
+	}
+	for n := 2; ; n++ {
+		if r := ref + "#" + strconv.Itoa(n); !used[r] {
+			return r
+		}
+	}
+}
+
+// sbomName returns the project name from a local path or repository URL.
+func sbomName(path string) string {
+	if abs, err := filepath.Abs(path); err == nil && !strings.Contains(path, "://") {
+		path = abs
+	}
+	return strings.TrimSuffix(filepath.Base(strings.TrimRight(path, "/")), ".git")
+}
+
+func toolVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// CycloneDX 1.5 JSON format.
+// See: https://cyclonedx.org/docs/1.5/json/
+type cycloneDXBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cycloneDXComponent `json:"components"`
+	} `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Scope      string              `json:"scope,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Licenses   []cycloneDXLicense  `json:"licenses,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+// cycloneDXLicense is either an SPDX expression or a named license.
+type cycloneDXLicense struct {
+	Expression string                 `json:"expression,omitempty"`
+	License    *cycloneDXNamedLicense `json:"license,omitempty"`
+}
+
+type cycloneDXNamedLicense struct {
+	Name string `json:"name"`
+}
+
+// cycloneDXLicenses returns a license, as an expression if it is a valid SPDX expression, or otherwise by name.
+func cycloneDXLicenses(l string) []cycloneDXLicense {
+	switch {
+	case l == "":
+		return nil
+	case license.IsValidExpression(l):
+		return []cycloneDXLicense{{Expression: l}}
+	default:
+		return []cycloneDXLicense{{License: &cycloneDXNamedLicense{Name: l}}}
+	}
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+// checksumAlgorithms maps the algorithms of dependency checksums to their CycloneDX and SPDX names.
+var checksumAlgorithms = map[string]struct{ cycloneDX, spdx string }{
+	"sha1":   {"SHA-1", "SHA1"},
+	"sha256": {"SHA-256", "SHA256"},
+	"sha384": {"SHA-384", "SHA384"},
+	"sha512": {"SHA-512", "SHA512"},
+}
+
+// cycloneDXHashes returns the checksum of a dependency, if its algorithm is supported.
+func cycloneDXHashes(d dep.Dependency) []cycloneDXHash {
+	algorithm, digest, _ := strings.Cut(d.Checksum, ":")
+	if names, ok := checksumAlgorithms[algorithm]; ok && digest != "" {
+		return []cycloneDXHash{{Alg: names.cycloneDX, Content: digest}}
+	}
+	return nil
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// outputCycloneDX writes the dependencies as a CycloneDX SBOM. Development dependencies have the "excluded" scope.
+// The root component depends on direct dependencies, and each component on its dependencies from lock files.
+func outputCycloneDX(s *sbom, stdout io.Writer) error {
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Components:   []cycloneDXComponent{},
+	}
+	bom.Metadata.Timestamp = s.created.Format(time.RFC3339)
+	bom.Metadata.Tools.Components = []cycloneDXComponent{{Type: "application", Name: "whatsun", Version: s.toolVersion}}
+	bom.Metadata.Component = cycloneDXComponent{
+		Type:     "application",
+		BOMRef:   "root",
+		Name:     s.name,
+		Licenses: cycloneDXLicenses(s.license),
+	}
+
+	root := cycloneDXDependency{Ref: "root", DependsOn: []string{}}
+	var dependencies []cycloneDXDependency
+	for i, info := range s.deps {
+		d := info.Dependency
+		scope := "required"
+		if d.IsDevOnly {
+			scope = "excluded"
+		}
+		bom.Components = append(bom.Components, cycloneDXComponent{
+			Type:     "library",
+			BOMRef:   s.refs[i],
+			Name:     d.Name,
+			Version:  d.Version,
+			Scope:    scope,
+			Hashes:   cycloneDXHashes(d),
+			Licenses: cycloneDXLicenses(d.License),
+			PURL:     dep.PackageURL(info.Manager, d),
+			Properties: []cycloneDXProperty{
+				{Name: "whatsun:path", Value: info.Path},
+				{Name: "whatsun:tool", Value: d.ToolName},
+				{Name: "whatsun:direct", Value: strconv.FormatBool(d.IsDirect)},
+			},
+		})
+		if d.IsDirect {
+			root.DependsOn = append(root.DependsOn, s.refs[i])
+		}
+		if children, ok := s.dependsOn[s.refs[i]]; ok {
+			dependencies = append(dependencies, cycloneDXDependency{Ref: s.refs[i], DependsOn: children})
+		}
+	}
+	bom.Dependencies = append([]cycloneDXDependency{root}, dependencies...)
+
+	return writeJSON(stdout, bom)
+}
+
+// SPDX 2.3 JSON format.
+// See: https://spdx.github.io/spdx-spec/v2.3/
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	Comment               string            `json:"comment,omitempty"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// spdxChecksums returns the checksum of a dependency, if its algorithm is supported.
+func spdxChecksums(d dep.Dependency) []spdxChecksum {
+	algorithm, digest, _ := strings.Cut(d.Checksum, ":")
+	if names, ok := checksumAlgorithms[algorithm]; ok && digest != "" {
+		return []spdxChecksum{{Algorithm: names.spdx, ChecksumValue: digest}}
+	}
+	return nil
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// outputSPDX writes the dependencies as an SPDX SBOM. The root package depends on direct dependencies (or has them
+// as development dependencies), and each package depends on its dependencies from lock files.
+func outputSPDX(s *sbom, stdout io.Writer) error {
+	const rootID = "SPDXRef-Root"
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.name,
+		DocumentNamespace: "https://spdx.org/spdxdocs/whatsun/" + url.PathEscape(s.name) + "-" + uuid.NewString(),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.created.Format(time.RFC3339),
+			Creators: []string{"Tool: whatsun-" + s.toolVersion},
+		},
+		DocumentDescribes: []string{rootID},
+		Packages: []spdxPackage{{
+			SPDXID:                rootID,
+			Name:                  s.name,
+			DownloadLocation:      "NOASSERTION",
+			LicenseConcluded:      "NOASSERTION",
+			LicenseDeclared:       spdxLicense(s.license),
+			CopyrightText:         "NOASSERTION",
+			PrimaryPackagePurpose: "APPLICATION",
+		}},
+		Relationships: []spdxRelationship{{"SPDXRef-DOCUMENT", "DESCRIBES", rootID}},
+	}
+
+	ids := make(map[string]string, len(s.deps))
+	for i, info := range s.deps {
+		d := info.Dependency
+		id := "SPDXRef-Package-" + strconv.Itoa(i+1)
+		ids[s.refs[i]] = id
+		comment := "Transitive dependency in " + info.Path
+		if d.IsDirect {
+			comment = "Direct dependency in " + info.Path
+		}
+		if d.IsDevOnly {
+			comment += " (development only)"
+		}
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:                id,
+			Name:                  d.Name,
+			VersionInfo:           d.Version,
+			DownloadLocation:      spdxDownloadLocation(d),
+			LicenseConcluded:      "NOASSERTION",
+			LicenseDeclared:       spdxLicense(d.License),
+			CopyrightText:         "NOASSERTION",
+			PrimaryPackagePurpose: "LIBRARY",
+			Comment:               comment,
+			Checksums:             spdxChecksums(d),
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  dep.PackageURL(info.Manager, d),
+			}},
+		})
+		switch {
+		case d.IsDirect && d.IsDevOnly:
+			doc.Relationships = append(doc.Relationships, spdxRelationship{id, "DEV_DEPENDENCY_OF", rootID})
+		case d.IsDirect:
+			doc.Relationships = append(doc.Relationships, spdxRelationship{rootID, "DEPENDS_ON", id})
+		}
+	}
+	for i := range s.deps {
+		for _, child := range s.dependsOn[s.refs[i]] {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{ids[s.refs[i]], "DEPENDS_ON", ids[child]})
+		}
+	}
+
+	return writeJSON(stdout, doc)
+}
+
+// spdxDownloadLocation returns the Git repository of a dependency, if known, e.g.
+// "git+https://github.com/example/repo.git@v1.0".
+func spdxDownloadLocation(d dep.Dependency) string {
+	if strings.HasPrefix(d.Source, "git+") {
+		return strings.Replace(d.Source, "#", "@", 1)
+	}
+	return "NOASSERTION"
+}
+
+// spdxLicense returns a license if it is a valid SPDX expression.
+func spdxLicense(l string) string {
+	if l != "" && license.IsValidExpression(l) {
+		return l
+	}
+	return "NOASSERTION"
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestSBOM_UniqueRefs(t *testing.T) {
+	// A pom.xml may list the same artifact twice, e.g. with the "test" scope and a "tests" classifier.
+	deps := []dependencyInfo{
+		{Path: ".", Manager: "java", Dependency: dep.Dependency{Name: "junit:junit", Version: "4.13.2"}},
+		{Path: ".", Manager: "java", Dependency: dep.Dependency{Name: "junit:junit", Version: "4.13.2"}},
+		{Path: ".", Manager: "java", Dependency: dep.Dependency{Name: "junit:junit", Version: "4.12"}},
+		{Path: "app", Manager: "java", Dependency: dep.Dependency{Name: "junit:junit"}},
+	}
+	s := newSBOM("test", "", deps, nil)
+	assert.Equal(t, []string{
+		".:java:junit:junit",
+		".:java:junit:junit@4.13.2",
+		".:java:junit:junit@4.12",
+		"app:java:junit:junit",
+	}, s.refs)
+
+	var buf bytes.Buffer
+	require.NoError(t, outputCycloneDX(s, &buf))
+	var bom cycloneDXBOM
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &bom))
+	refs := make(map[string]bool)
+	for _, c := range bom.Components {
+		assert.False(t, refs[c.BOMRef], "duplicate bom-ref %s", c.BOMRef)
+		refs[c.BOMRef] = true
+	}
+	assert.Len(t, refs, len(deps))
+}
+
+func TestUniqueRef(t *testing.T) {
+	used := map[string]bool{"a": true, "a@1.0": true, "a#2": true}
+	assert.Equal(t, "b", uniqueRef("b", "1.0", used))
+	assert.Equal(t, "a@2.0", uniqueRef("a", "2.0", used))
+	assert.Equal(t, "a#3", uniqueRef("a", "1.0", used))
+	assert.Equal(t, "a#3", uniqueRef("a", "", used))
+}
@@ -2,20 +2,20 @@ package fsdir
 
 import (
 	"io/fs"
-	"unsafe"
+
+	"github.com/upsun/whatsun/pkg/session"
 )
 
-// FSDir represents a single directory in a filesystem.
+// FSDir represents a single directory in the filesystem of an analysis session.
 type FSDir struct {
-	fs   fs.FS
-	path string
-	id   uintptr // Unique ID of the filesystem to be used for comparison (e.g. as a cache key).
+	session *session.Session
+	path    string
 }
 
-func New(fsys fs.FS, path string) FSDir {
-	return FSDir{fs: fsys, path: path, id: uintptr(unsafe.Pointer(&fsys))}
+func New(s *session.Session, path string) FSDir {
+	return FSDir{session: s, path: path}
 }
 
-func (f FSDir) ID() uintptr  { return f.id }
-func (f FSDir) Path() string { return f.path }
-func (f FSDir) FS() fs.FS    { return f.fs }
+func (f FSDir) Session() *session.Session { return f.session }
+func (f FSDir) Path() string              { return f.path }
+func (f FSDir) FS() fs.FS                 { return f.session.FS() }
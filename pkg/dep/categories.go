@@ -0,0 +1,170 @@
+package dep
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Categories describe the role of a dependency in a project.
+const (
+	CategoryFramework  = "framework"  // Web or application frameworks.
+	CategoryDatabase   = "database"   // ORMs, query builders and database drivers.
+	CategoryTesting    = "testing"    // Test frameworks, runners and tools.
+	CategoryLinting    = "linting"    // Linters, formatters and static analysis.
+	CategoryBuild      = "build"      // Build tools, bundlers and compilers.
+	CategoryTypes      = "types"      // Type definitions only.
+	CategoryMonitoring = "monitoring" // Error tracking, logging, metrics and tracing.
+)
+
+//go:embed categories.yml
+var defaultCategories []byte
+
+// categoryRule is an entry in the YAML category mapping.
+type categoryRule struct {
+	Category string   `yaml:"category"`
+	Packages []string `yaml:"packages"`
+}
+
+type categoryPattern struct {
+	pattern  string
+	category string
+}
+
+var (
+	categoriesMu   sync.RWMutex
+	categoriesOnce sync.Once
+	// Categories by manager type and exact package name.
+	categoryNames = make(map[string]map[string]string)
+	// Categories by manager type, with wildcard patterns in order of precedence.
+	categoryPatterns = make(map[string][]categoryPattern)
+)
+
+// loadDefaultCategories loads the embedded mapping, once, before any categories are added or read.
+func loadDefaultCategories() {
+	categoriesOnce.Do(func() {
+		if err := loadCategories(defaultCategories, false); err != nil {
+			panic(fmt.Sprintf("failed to load default dependency categories: %v", err))
+		}
+	})
+}
+
+// LoadCategories adds categories from a YAML mapping, in the same format as the embedded categories.yml file: a map
+// of manager types to lists of categories with package name patterns. The new categories take precedence over
+// existing ones, including those of exact names matching a new pattern.
+func LoadCategories(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	loadDefaultCategories()
+	return loadCategories(b, true)
+}
+
+func loadCategories(b []byte, precede bool) error {
+	var mapping map[string][]categoryRule
+	if err := yaml.Unmarshal(b, &mapping); err != nil {
+		return fmt.Errorf("failed to parse categories: %w", err)
+	}
+	for managerType, rules := range mapping {
+		for _, r := range rules {
+			if r.Category == "" {
+				return fmt.Errorf("missing category for manager type %q", managerType)
+			}
+		}
+	}
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	for managerType, rules := range mapping {
+		if precede {
+			for _, r := range rules {
+				removeShadowedNames(managerType, r.Packages)
+			}
+		}
+		var patterns []categoryPattern
+		for _, r := range rules {
+			patterns = append(patterns, addCategory(managerType, r.Category, r.Packages, precede)...)
+		}
+		if precede {
+			categoryPatterns[managerType] = append(patterns, categoryPatterns[managerType]...)
+		} else {
+			categoryPatterns[managerType] = append(categoryPatterns[managerType], patterns...)
+		}
+	}
+	return nil
+}
+
+// addCategory records the exact names of packages in a category, and returns the wildcard patterns.
+// The categoriesMu lock must be held.
+func addCategory(managerType, category string, packages []string, precede bool) []categoryPattern {
+	var patterns []categoryPattern
+	for _, p := range packages {
+		if strings.Contains(p, "*") {
+			patterns = append(patterns, categoryPattern{pattern: p, category: category})
+			continue
+		}
+		names, ok := categoryNames[managerType]
+		if !ok {
+			names = make(map[string]string)
+			categoryNames[managerType] = names
+		}
+		if _, exists := names[p]; !exists || precede {
+			names[p] = category
+		}
+	}
+	return patterns
+}
+
+// removeShadowedNames removes the existing exact names that match new patterns, so that the patterns take precedence.
+// The categoriesMu lock must be held.
+func removeShadowedNames(managerType string, packages []string) {
+	for _, p := range packages {
+		if !strings.Contains(p, "*") {
+			continue
+		}
+		for name := range categoryNames[managerType] {
+			if wildcard.Match(p, name) {
+				delete(categoryNames[managerType], name)
+			}
+		}
+	}
+}
+
+// AddCategory assigns a category to packages of a manager type, given as names or wildcard patterns. It takes
+// precedence over existing categories, including those of exact names matching a new pattern.
+func AddCategory(managerType, category string, packages ...string) {
+	loadDefaultCategories()
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	removeShadowedNames(managerType, packages)
+	patterns := addCategory(managerType, category, packages, true)
+	categoryPatterns[managerType] = append(patterns, categoryPatterns[managerType]...)
+}
+
+// CategoryOf returns the category of a package, or an empty string if it is not known.
+func CategoryOf(managerType, name string) string {
+	loadDefaultCategories()
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+	if c, ok := categoryNames[managerType][name]; ok {
+		return c
+	}
+	for _, p := range categoryPatterns[managerType] {
+		if wildcard.Match(p.pattern, name) {
+			return p.category
+		}
+	}
+	return ""
+}
+
+// Categorize sets the Category of each dependency of a manager type.
+func Categorize(managerType string, deps []Dependency) {
+	for i := range deps {
+		deps[i].Category = CategoryOf(managerType, deps[i].Name)
+	}
+}
@@ -0,0 +1,67 @@
+package dep_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestCategoryOf(t *testing.T) {
+	cases := []struct {
+		managerType string
+		name        string
+		category    string
+	}{
+		{dep.ManagerTypeJavaScript, "next", dep.CategoryFramework},
+		{dep.ManagerTypeJavaScript, "@types/node", dep.CategoryTypes},
+		{dep.ManagerTypeJavaScript, "@testing-library/react", dep.CategoryTesting},
+		{dep.ManagerTypeJavaScript, "eslint-plugin-react", dep.CategoryLinting},
+		{dep.ManagerTypeJavaScript, "css-loader", dep.CategoryBuild},
+		{dep.ManagerTypeJavaScript, "@sentry/node", dep.CategoryMonitoring},
+		{dep.ManagerTypeJavaScript, "left-pad", ""},
+		{dep.ManagerTypePHP, "doctrine/orm", dep.CategoryDatabase},
+		{dep.ManagerTypePHP, "phpstan/phpstan", dep.CategoryLinting},
+		{dep.ManagerTypePython, "pytest-django", dep.CategoryTesting},
+		{dep.ManagerTypeGo, "github.com/jackc/pgx/v5", dep.CategoryDatabase},
+		{dep.ManagerTypeJava, "org.springframework.boot:spring-boot-starter-web", dep.CategoryFramework},
+
+		// Names are only matched for their own manager type.
+		{dep.ManagerTypeRuby, "next", ""},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.category, dep.CategoryOf(c.managerType, c.name), c.managerType+" "+c.name)
+	}
+}
+
+func TestAddCategory(t *testing.T) {
+	const managerType = "test-categories"
+	require.NoError(t, dep.LoadCategories(strings.NewReader(`
+test-categories:
+  - category: framework
+    packages: [acme/web, acme/*]
+  - category: testing
+    packages: [acme/test-*]
+`)))
+	assert.Equal(t, dep.CategoryFramework, dep.CategoryOf(managerType, "acme/web"))
+	assert.Equal(t, dep.CategoryFramework, dep.CategoryOf(managerType, "acme/test-utils"))
+
+	// Added categories take precedence, and exact names take precedence over patterns.
+	dep.AddCategory(managerType, "internal", "acme/test-*", "acme/web")
+	assert.Equal(t, "internal", dep.CategoryOf(managerType, "acme/test-utils"))
+	assert.Equal(t, "internal", dep.CategoryOf(managerType, "acme/web"))
+	assert.Equal(t, dep.CategoryFramework, dep.CategoryOf(managerType, "acme/api"))
+
+	// An added pattern also takes precedence over the existing exact names that it matches.
+	dep.AddCategory(managerType, "web", "acme/w*")
+	assert.Equal(t, "web", dep.CategoryOf(managerType, "acme/web"))
+
+	deps := []dep.Dependency{{Name: "acme/api"}, {Name: "other"}}
+	dep.Categorize(managerType, deps)
+	assert.Equal(t, []dep.Dependency{{Name: "acme/api", Category: dep.CategoryFramework}, {Name: "other"}}, deps)
+
+	assert.Error(t, dep.LoadCategories(strings.NewReader("test-categories: [{packages: [a]}]")))
+}
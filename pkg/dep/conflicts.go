@@ -0,0 +1,93 @@
+package dep
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Usage is a dependency found in a directory.
+type Usage struct {
+	Path        string // The directory path.
+	ManagerType string // The manager type, e.g. "js".
+	Dependency  Dependency
+}
+
+// ConflictKind describes a way in which the usages of a dependency differ.
+type ConflictKind string
+
+const (
+	ConflictConstraint ConflictKind = "constraint" // The version constraints differ.
+	ConflictVersion    ConflictKind = "version"    // The resolved versions differ.
+	ConflictTool       ConflictKind = "tool"       // The dependency is declared under different tools.
+)
+
+// Conflict is a dependency that is used inconsistently, e.g. across the apps in a monorepo.
+type Conflict struct {
+	ManagerType string
+	Name        string
+	Kinds       []ConflictKind // The ways in which the usages differ.
+	Usages      []Usage        // All the usages of the dependency, sorted by path.
+}
+
+// FindConflicts groups dependencies by manager type and name across all paths, and returns the groups whose
+// constraints, resolved versions or tool names differ. Empty constraints and versions are not compared, and versions
+// that are equal according to CompareVersions (e.g. "v1.2" and "1.2.0") do not conflict.
+//
+// Conflicts are sorted by manager type and name.
+func FindConflicts(usages []Usage) []Conflict {
+	type key struct{ managerType, name string }
+	groups := make(map[key][]Usage)
+	for _, u := range usages {
+		k := key{u.ManagerType, u.Dependency.Name}
+		groups[k] = append(groups[k], u)
+	}
+
+	var conflicts []Conflict
+	for k, group := range groups {
+		var kinds []ConflictKind
+		if differ(group, func(d Dependency) string { return strings.TrimSpace(d.Constraint) }, nil) {
+			kinds = append(kinds, ConflictConstraint)
+		}
+		if differ(group, func(d Dependency) string { return d.Version }, equalVersions) {
+			kinds = append(kinds, ConflictVersion)
+		}
+		if differ(group, func(d Dependency) string { return d.ToolName }, nil) {
+			kinds = append(kinds, ConflictTool)
+		}
+		if len(kinds) == 0 {
+			continue
+		}
+		slices.SortStableFunc(group, func(a, b Usage) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+		conflicts = append(conflicts, Conflict{ManagerType: k.managerType, Name: k.name, Kinds: kinds, Usages: group})
+	}
+	slices.SortFunc(conflicts, func(a, b Conflict) int {
+		return cmp.Or(strings.Compare(a.ManagerType, b.ManagerType), strings.Compare(a.Name, b.Name))
+	})
+	return conflicts
+}
+
+// differ checks if the non-empty values of a field differ between usages. Values are compared with the equal
+// function, or with == if it is nil.
+func differ(usages []Usage, field func(Dependency) string, equal func(a, b string) bool) bool {
+	var first string
+	for _, u := range usages {
+		v := field(u.Dependency)
+		switch {
+		case v == "":
+		case first == "":
+			first = v
+		case v != first && (equal == nil || !equal(first, v)):
+			return true
+		}
+	}
+	return false
+}
+
+// equalVersions checks if two different strings represent equal versions.
+func equalVersions(a, b string) bool {
+	c, err := CompareVersions(a, b)
+	return err == nil && c == 0
+}
@@ -0,0 +1,58 @@
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestFindConflicts(t *testing.T) {
+	usages := []dep.Usage{
+		{Path: "web", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "react", Constraint: "^18.2", Version: "18.2.0", ToolName: "npm"}},
+		{Path: "admin", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "react", Constraint: "^17.0", Version: "17.0.2", ToolName: "npm"}},
+		{Path: "docs", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "react", Constraint: "^18.2", ToolName: "yarn"}},
+
+		// The same constraint, with equivalent versions.
+		{Path: "web", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "lodash", Constraint: "^4.17", Version: "4.17.21", ToolName: "npm"}},
+		{Path: "admin", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "lodash", Constraint: "^4.17", Version: "v4.17.21", ToolName: "npm"}},
+
+		// Another manager type, with different versions, and a dependency used only once.
+		{Path: "api", ManagerType: "php", Dependency: dep.Dependency{
+			Name: "symfony/console", Constraint: "^7.2", Version: "v7.2.1", ToolName: "composer"}},
+		{Path: "backoffice", ManagerType: "php", Dependency: dep.Dependency{
+			Name: "symfony/console", Constraint: "^7.2", Version: "v7.1.8", ToolName: "composer"}},
+		{Path: "backoffice", ManagerType: "php", Dependency: dep.Dependency{
+			Name: "symfony/yaml", Constraint: "^7.1", ToolName: "composer"}},
+
+		// The same name under different manager types, which is not a conflict.
+		{Path: "web", ManagerType: "js", Dependency: dep.Dependency{
+			Name: "redis", Constraint: "^4.6", Version: "4.6.13", ToolName: "npm"}},
+		{Path: "worker", ManagerType: "python", Dependency: dep.Dependency{
+			Name: "redis", Constraint: ">=5.0", Version: "5.0.1", ToolName: "uv"}},
+	}
+
+	conflicts := dep.FindConflicts(usages)
+	assert.Equal(t, []dep.Conflict{
+		{
+			ManagerType: "js",
+			Name:        "react",
+			Kinds:       []dep.ConflictKind{dep.ConflictConstraint, dep.ConflictVersion, dep.ConflictTool},
+			Usages:      []dep.Usage{usages[1], usages[2], usages[0]},
+		},
+		{
+			ManagerType: "php",
+			Name:        "symfony/console",
+			Kinds:       []dep.ConflictKind{dep.ConflictVersion},
+			Usages:      []dep.Usage{usages[5], usages[6]},
+		},
+	}, conflicts)
+
+	assert.Empty(t, dep.FindConflicts(nil))
+}
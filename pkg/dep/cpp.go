@@ -0,0 +1,400 @@
+package dep
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+// cppManager reads C and C++ dependencies managed by vcpkg or Conan.
+type cppManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	diagnostics
+}
+
+func newCppManager(fsys fs.FS, path string) Manager {
+	return &cppManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *cppManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+func (m *cppManager) parse() error {
+	m.deps = make(map[string]Dependency)
+	if err := m.parseVcpkg(); err != nil {
+		return err
+	}
+	return m.parseConan()
+}
+
+func (m *cppManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *cppManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// addDependency adds a dependency, where regular dependencies take precedence over dev dependencies.
+func (m *cppManager) addDependency(d Dependency) {
+	if existing, ok := m.deps[d.Name]; ok && !existing.IsDevOnly {
+		return
+	}
+	m.deps[d.Name] = d
+}
+
+// vcpkgManifest is the vcpkg.json file.
+// See: https://learn.microsoft.com/en-us/vcpkg/reference/vcpkg-json
+type vcpkgManifest struct {
+	Dependencies []vcpkgDependency `json:"dependencies"`
+	Features     map[string]struct {
+		Dependencies []vcpkgDependency `json:"dependencies"`
+	} `json:"features"`
+	Overrides []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"overrides"`
+	Configuration *vcpkgConfiguration `json:"vcpkg-configuration"`
+}
+
+// vcpkgDependency is a dependency, which is either a port name or an object.
+type vcpkgDependency struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"version>="`
+}
+
+func (d *vcpkgDependency) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &d.Name); err == nil {
+		return nil
+	}
+	type plain vcpkgDependency
+	return json.Unmarshal(b, (*plain)(d))
+}
+
+// vcpkgConfiguration is the vcpkg-configuration.json file, which can also be embedded in vcpkg.json.
+// See: https://learn.microsoft.com/en-us/vcpkg/reference/vcpkg-configuration-json
+type vcpkgConfiguration struct {
+	DefaultRegistry *vcpkgRegistry  `json:"default-registry"`
+	Registries      []vcpkgRegistry `json:"registries"`
+}
+
+type vcpkgRegistry struct {
+	Kind       string   `json:"kind"`
+	Repository string   `json:"repository"`
+	Path       string   `json:"path"`
+	Baseline   string   `json:"baseline"`
+	Packages   []string `json:"packages"`
+}
+
+// source formats the registry and its baseline as a dependency source.
+func (r *vcpkgRegistry) source() string {
+	switch r.Kind {
+	case "git":
+		if r.Baseline != "" {
+			return "git+" + r.Repository + "#" + r.Baseline
+		}
+		return "git+" + r.Repository
+	case "filesystem":
+		return "path+" + r.Path
+	}
+	return ""
+}
+
+// registrySource finds the source of a port from the configured registries.
+func (c *vcpkgConfiguration) registrySource(name string) string {
+	if c == nil {
+		return ""
+	}
+	for _, r := range c.Registries {
+		for _, p := range r.Packages {
+			if wildcard.Match(p, name) {
+				return r.source()
+			}
+		}
+	}
+	if c.DefaultRegistry != nil {
+		return c.DefaultRegistry.source()
+	}
+	return ""
+}
+
+func (m *cppManager) parseVcpkg() error {
+	var manifest vcpkgManifest
+	if err := parseJSON(m.fsys, m.path, "vcpkg.json", &manifest); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+
+	config := manifest.Configuration
+	if config == nil {
+		var fileConfig vcpkgConfiguration
+		err := parseJSON(m.fsys, m.path, "vcpkg-configuration.json", &fileConfig)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) && m.report(err) != nil {
+			return err
+		}
+		if err == nil {
+			config = &fileConfig
+		}
+	}
+
+	add := func(vd vcpkgDependency, devOnly bool) {
+		d := Dependency{
+			Name:      vd.Name,
+			IsDirect:  true,
+			IsDevOnly: devOnly,
+			ToolName:  "vcpkg",
+			Source:    config.registrySource(vd.Name),
+		}
+		if vd.MinVersion != "" {
+			d.Constraint = ">= " + vd.MinVersion
+		}
+		m.addDependency(d)
+	}
+	for _, vd := range manifest.Dependencies {
+		add(vd, false)
+	}
+	for name, feature := range manifest.Features {
+		for _, vd := range feature.Dependencies {
+			add(vd, name == "test" || name == "tests")
+		}
+	}
+	// Overrides pin a port to an exact version.
+	for _, o := range manifest.Overrides {
+		if d, ok := m.deps[o.Name]; ok {
+			d.Constraint = o.Version
+			m.deps[o.Name] = d
+		}
+	}
+	return nil
+}
+
+func (m *cppManager) parseConan() error {
+	for _, filename := range []string{"conanfile.txt", "conanfile.py"} {
+		f, err := m.fsys.Open(filepath.Join(m.path, filename))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		var deps []Dependency
+		if filename == "conanfile.txt" {
+			deps, err = parseConanfileTxt(f)
+		} else {
+			deps, err = parseConanfilePy(f)
+		}
+		f.Close()
+		if err != nil {
+			return err
+		}
+		for _, d := range deps {
+			m.addDependency(d)
+		}
+	}
+
+	f, err := m.fsys.Open(filepath.Join(m.path, "conan.lock"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	locked, err := parseConanLock(f)
+	if err != nil {
+		return m.report(newDiagnostic(filepath.Join(m.path, "conan.lock"), nil, err))
+	}
+	for _, ref := range locked {
+		if d, ok := m.deps[ref.name]; ok {
+			if d.ToolName == "conan" {
+				// Update existing dependency (preserve IsDirect status)
+				d.Version = ref.version
+				m.deps[ref.name] = d
+			}
+			continue
+		}
+		// New dependency only in the lock file (indirect)
+		m.deps[ref.name] = Dependency{
+			Name:     ref.name,
+			Version:  ref.version,
+			ToolName: "conan",
+		}
+	}
+	return nil
+}
+
+// conanReference is a parsed Conan package reference.
+type conanReference struct {
+	name    string
+	version string
+}
+
+// parseConanReference parses a reference in the format "name/version@user/channel#revision".
+// The version may be a range in square brackets, e.g. "openssl/[>=3.0 <4]".
+func parseConanReference(ref string) (conanReference, bool) {
+	ref = strings.TrimSpace(ref)
+	if i := strings.IndexAny(ref, "@#"); i != -1 {
+		ref = ref[:i]
+	}
+	name, version, ok := strings.Cut(ref, "/")
+	if !ok || name == "" {
+		return conanReference{}, false
+	}
+	return conanReference{name: name, version: strings.Trim(version, "[]")}, true
+}
+
+// conanRequirementKinds maps requirement types to whether they are development-only.
+var conanRequirementKinds = map[string]bool{
+	"requires":       false,
+	"tool_requires":  false,
+	"build_requires": false,
+	"test_requires":  true,
+}
+
+// parseConanfileTxt parses the requirements sections of a conanfile.txt file.
+// See: https://docs.conan.io/2/reference/conanfile_txt.html
+func parseConanfileTxt(r io.Reader) ([]Dependency, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		deps    []Dependency
+		devOnly bool
+		inReqs  bool
+	)
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			devOnly, inReqs = conanRequirementKinds[strings.Trim(line, "[]")]
+			continue
+		}
+		if !inReqs {
+			continue
+		}
+		if ref, ok := parseConanReference(line); ok {
+			deps = append(deps, conanDependency(ref, devOnly))
+		}
+	}
+	return deps, nil
+}
+
+var (
+	conanAttrPatt   = regexp.MustCompile(`(?m)^\s*((?:tool_|build_|test_)?requires)\s*=\s*`)
+	conanMethodPatt = regexp.MustCompile(`self\.((?:tool_|build_|test_)?requires)\(\s*["']([^"']+)["']`)
+	conanQuotedPatt = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// parseConanfilePy finds requirements in a conanfile.py recipe, from class attributes such as
+// `requires = "zlib/1.3", "fmt/10.1.1"` and method calls such as `self.requires("zlib/1.3")`.
+// See: https://docs.conan.io/2/reference/conanfile/attributes.html#requires
+func parseConanfilePy(r io.Reader) ([]Dependency, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content := string(b)
+	var deps []Dependency
+
+	for _, loc := range conanAttrPatt.FindAllStringSubmatchIndex(content, -1) {
+		devOnly := conanRequirementKinds[content[loc[2]:loc[3]]]
+		value := content[loc[1]:]
+		if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "(") {
+			if end := matchingBracket(value, 0); end != -1 {
+				value = value[:end]
+			}
+		} else if i := strings.IndexByte(value, '\n'); i != -1 {
+			value = value[:i]
+		}
+		for _, q := range conanQuotedPatt.FindAllStringSubmatch(value, -1) {
+			if ref, ok := parseConanReference(q[1]); ok {
+				deps = append(deps, conanDependency(ref, devOnly))
+			}
+		}
+	}
+
+	for _, matches := range conanMethodPatt.FindAllStringSubmatch(content, -1) {
+		if ref, ok := parseConanReference(matches[2]); ok {
+			deps = append(deps, conanDependency(ref, conanRequirementKinds[matches[1]]))
+		}
+	}
+
+	return deps, nil
+}
+
+func conanDependency(ref conanReference, devOnly bool) Dependency {
+	return Dependency{
+		Name:       ref.name,
+		Constraint: ref.version,
+		IsDirect:   true,
+		IsDevOnly:  devOnly,
+		ToolName:   "conan",
+	}
+}
+
+// conanLock is the conan.lock file, in the format of Conan 2 or Conan 1.
+type conanLock struct {
+	Requires       []string `json:"requires"`
+	BuildRequires  []string `json:"build_requires"`
+	PythonRequires []string `json:"python_requires"`
+	GraphLock      *struct {
+		Nodes map[string]struct {
+			Ref string `json:"ref"`
+		} `json:"nodes"`
+	} `json:"graph_lock"`
+}
+
+// parseConanLock parses the locked package references.
+func parseConanLock(r io.Reader) ([]conanReference, error) {
+	var lock conanLock
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, err
+	}
+	refs := slices.Concat(lock.Requires, lock.BuildRequires, lock.PythonRequires)
+	if lock.GraphLock != nil {
+		for _, node := range lock.GraphLock.Nodes {
+			if node.Ref != "" {
+				refs = append(refs, node.Ref)
+			}
+		}
+	}
+	var locked []conanReference
+	for _, ref := range refs {
+		if parsed, ok := parseConanReference(ref); ok {
+			locked = append(locked, parsed)
+		}
+	}
+	return locked, nil
+}
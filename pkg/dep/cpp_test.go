@@ -0,0 +1,137 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestVcpkg(t *testing.T) {
+	fsys := fstest.MapFS{
+		"vcpkg.json": {Data: []byte(`{
+  "name": "native-service",
+  "version": "1.2.0",
+  "dependencies": [
+    "fmt",
+    { "name": "boost-asio", "version>=": "1.83.0" },
+    { "name": "beicode", "host": true }
+  ],
+  "features": {
+    "tests": {
+      "description": "Build the tests",
+      "dependencies": ["gtest", "fmt"]
+    }
+  },
+  "overrides": [
+    { "name": "fmt", "version": "10.1.1" }
+  ]
+}`)},
+		"vcpkg-configuration.json": {Data: []byte(`{
+  "default-registry": {
+    "kind": "git",
+    "repository": "https://github.com/microsoft/vcpkg",
+    "baseline": "c8696863d371ab7f46e213d8f5ca923c4aef2a00"
+  },
+  "registries": [
+    {
+      "kind": "git",
+      "repository": "https://github.com/northwindtraders/vcpkg-registry",
+      "baseline": "dacf4de488094a384ca2c202b923ccc097956e0c",
+      "packages": ["beicode", "beison"]
+    }
+  ]
+}`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeCpp, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	const defaultRegistry = "git+https://github.com/microsoft/vcpkg#c8696863d371ab7f46e213d8f5ca923c4aef2a00"
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "fmt", Constraint: "10.1.1", IsDirect: true, ToolName: "vcpkg", Source: defaultRegistry},
+		{Name: "boost-asio", Constraint: ">= 1.83.0", IsDirect: true, ToolName: "vcpkg", Source: defaultRegistry},
+		{
+			Name:     "beicode",
+			IsDirect: true,
+			ToolName: "vcpkg",
+			Source:   "git+https://github.com/northwindtraders/vcpkg-registry#dacf4de488094a384ca2c202b923ccc097956e0c",
+		},
+		{Name: "gtest", IsDirect: true, IsDevOnly: true, ToolName: "vcpkg", Source: defaultRegistry},
+	}, m.Find("*"))
+}
+
+func TestConan(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		contents string
+	}{
+		{"conanfile.txt", "conanfile.txt", `[requires]
+zlib/1.3
+openssl/[>=3.0 <4]
+fmt/10.1.1@acme/stable#a1b2c3
+
+[tool_requires]
+cmake/3.27.7
+
+[test_requires]
+gtest/1.14.0
+
+[generators]
+CMakeDeps
+CMakeToolchain
+`},
+		{"conanfile.py attributes", "conanfile.py", `from conan import ConanFile
+
+
+class NativeService(ConanFile):
+    settings = "os", "compiler", "build_type", "arch"
+    generators = "CMakeDeps", "CMakeToolchain"
+    requires = (
+        "zlib/1.3",
+        "openssl/[>=3.0 <4]",
+    )
+    tool_requires = "cmake/3.27.7"
+
+    def requirements(self):
+        self.requires("fmt/10.1.1@acme/stable")
+        self.test_requires("gtest/1.14.0")
+`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fsys := fstest.MapFS{
+				c.filename: {Data: []byte(c.contents)},
+				"conan.lock": {Data: []byte(`{
+    "version": "0.5",
+    "requires": [
+        "zlib/1.3#06023034579559bb64357db3a53f88a4%1699359823.373",
+        "openssl/3.2.0#1e6fd8b1c8c8a2a76c2a0a8a20a1bf4e%1702552436.436",
+        "fmt/10.1.1#f5d6a2f5e4d1c5c3f8b6e6f2b3e1c7a9%1695308364.584"
+    ],
+    "build_requires": [
+        "cmake/3.27.7#9d4f4d62a2e3e0a4b8e07a0f2a3c4b5d%1698154562.521"
+    ],
+    "python_requires": []
+}`)},
+			}
+
+			m, err := dep.GetManager(dep.ManagerTypeCpp, fsys, ".")
+			require.NoError(t, err)
+			require.NoError(t, m.Init())
+
+			assert.ElementsMatch(t, []dep.Dependency{
+				{Name: "zlib", Constraint: "1.3", Version: "1.3", IsDirect: true, ToolName: "conan"},
+				{Name: "openssl", Constraint: ">=3.0 <4", Version: "3.2.0", IsDirect: true, ToolName: "conan"},
+				{Name: "fmt", Constraint: "10.1.1", Version: "10.1.1", IsDirect: true, ToolName: "conan"},
+				{Name: "cmake", Constraint: "3.27.7", Version: "3.27.7", IsDirect: true, ToolName: "conan"},
+				{Name: "gtest", Constraint: "1.14.0", IsDirect: true, IsDevOnly: true, ToolName: "conan"},
+			}, m.Find("*"))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+type dartManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	sdks     []Dependency
+	diagnostics
+}
+
+func newDartManager(fsys fs.FS, path string) Manager {
+	return &dartManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *dartManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+// pubspec is the pubspec.yaml file.
+// See: https://dart.dev/tools/pub/pubspec
+type pubspec struct {
+	Environment         map[string]string `yaml:"environment"`
+	Dependencies        map[string]any    `yaml:"dependencies"`
+	DevDependencies     map[string]any    `yaml:"dev_dependencies"`
+	DependencyOverrides map[string]any    `yaml:"dependency_overrides"`
+}
+
+// pubspecLock is the pubspec.lock file.
+type pubspecLock struct {
+	Packages map[string]struct {
+		Dependency  string `yaml:"dependency"` // e.g. "direct main", "direct dev", "transitive"
+		Description any    `yaml:"description"`
+		Source      string `yaml:"source"` // "hosted", "git", "path" or "sdk"
+		Version     string `yaml:"version"`
+	} `yaml:"packages"`
+}
+
+func (m *dartManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
+	var spec pubspec
+	if err := parseYAML(m.fsys, m.path, "pubspec.yaml", &spec); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+
+	for name, v := range spec.DevDependencies {
+		constraint, source := parsePubDependency(v)
+		m.deps[name] = Dependency{
+			Name:       name,
+			Constraint: constraint,
+			IsDirect:   true,
+			IsDevOnly:  true,
+			ToolName:   "pub",
+			Source:     source,
+		}
+	}
+	// Regular dependencies take precedence over dev dependencies.
+	for name, v := range spec.Dependencies {
+		constraint, source := parsePubDependency(v)
+		m.deps[name] = Dependency{
+			Name:       name,
+			Constraint: constraint,
+			IsDirect:   true,
+			ToolName:   "pub",
+			Source:     source,
+		}
+	}
+	// Overrides replace the constraint and source of any dependency, including transitive ones.
+	for name, v := range spec.DependencyOverrides {
+		constraint, source := parsePubDependency(v)
+		d, ok := m.deps[name]
+		if !ok {
+			d = Dependency{Name: name, ToolName: "pub"}
+		}
+		d.Constraint = constraint
+		d.Source = source
+		m.deps[name] = d
+	}
+
+	// The Dart SDK constraint is listed first, followed by others such as "flutter".
+	if constraint, ok := spec.Environment["sdk"]; ok {
+		m.sdks = append(m.sdks, Dependency{Name: "dart", Constraint: constraint, ToolName: "pub"})
+	}
+	sdkNames := make([]string, 0, len(spec.Environment))
+	for name := range spec.Environment {
+		if name != "sdk" {
+			sdkNames = append(sdkNames, name)
+		}
+	}
+	sort.Strings(sdkNames)
+	for _, name := range sdkNames {
+		m.sdks = append(m.sdks, Dependency{Name: name, Constraint: spec.Environment[name], ToolName: "pub"})
+	}
+
+	var lock pubspecLock
+	if err := parseYAML(m.fsys, m.path, "pubspec.lock", &lock); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+	for name, pkg := range lock.Packages {
+		if d, ok := m.deps[name]; ok {
+			// Update existing dependency (preserve IsDirect status)
+			d.Version = pkg.Version
+			if pkg.Source == "git" {
+				d.Source = pubLockSource(pkg.Source, pkg.Description) // Includes the resolved revision
+			}
+			m.deps[name] = d
+		} else {
+			// New dependency only in the lock file (indirect)
+			m.deps[name] = Dependency{
+				Name:      name,
+				Version:   pkg.Version,
+				IsDevOnly: pkg.Dependency == "direct dev",
+				ToolName:  "pub",
+				Source:    pubLockSource(pkg.Source, pkg.Description),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *dartManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *dartManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Platform returns the SDK constraints from the "environment" section of pubspec.yaml, e.g. "dart" and "flutter".
+func (m *dartManager) Platform() []Dependency {
+	return m.sdks
+}
+
+// parsePubDependency parses a dependency specification from pubspec.yaml, which is either a version constraint or
+// a map describing a hosted, git, path or SDK source.
+// See: https://dart.dev/tools/pub/dependencies
+func parsePubDependency(v any) (constraint, source string) {
+	switch spec := v.(type) {
+	case string:
+		return spec, ""
+	case map[string]any:
+		if version, ok := spec["version"]; ok {
+			constraint = fmt.Sprint(version)
+		}
+		switch {
+		case spec["sdk"] != nil:
+			source = "sdk+" + fmt.Sprint(spec["sdk"])
+		case spec["path"] != nil:
+			source = "path+" + fmt.Sprint(spec["path"])
+		case spec["git"] != nil:
+			source = pubGitSource(spec["git"], "ref")
+		}
+	}
+	return constraint, source
+}
+
+// pubGitSource formats a git source from a URL string or a map containing the "url" and a revision.
+func pubGitSource(v any, refKey string) string {
+	switch git := v.(type) {
+	case string:
+		return "git+" + git
+	case map[string]any:
+		source := "git+" + fmt.Sprint(git["url"])
+		if ref, ok := git[refKey]; ok && ref != nil {
+			source += "#" + fmt.Sprint(ref)
+		}
+		return source
+	}
+	return ""
+}
+
+// pubLockSource formats the source of a package in pubspec.lock.
+func pubLockSource(source string, description any) string {
+	switch source {
+	case "git":
+		return pubGitSource(description, "resolved-ref")
+	case "path":
+		if d, ok := description.(map[string]any); ok {
+			return "path+" + fmt.Sprint(d["path"])
+		}
+	case "sdk":
+		return "sdk+" + fmt.Sprint(description)
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestDart(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pubspec.yaml": {Data: []byte(`name: my_app
+publish_to: none
+version: 1.0.0+1
+
+environment:
+  sdk: ">=3.2.0 <4.0.0"
+  flutter: ">=3.16.0"
+
+dependencies:
+  flutter:
+    sdk: flutter
+  http: ^1.1.0
+  shared_models:
+    path: ../shared_models
+  markdown_editor:
+    git:
+      url: https://github.com/example/markdown_editor.git
+      ref: main
+  private_pkg:
+    hosted: https://pub.example.com
+    version: ^2.0.0
+  collection: any
+
+dev_dependencies:
+  flutter_test:
+    sdk: flutter
+  flutter_lints: ^3.0.1
+
+dependency_overrides:
+  meta: 1.11.0
+`)},
+		"pubspec.lock": {Data: []byte(`# Generated by pub
+packages:
+  flutter_lints:
+    dependency: "direct dev"
+    description:
+      name: flutter_lints
+      sha256: e2a421b7e59244faef694ba7b30562e489c2b489866e505074eb005cd7060db7
+      url: "https://pub.dev"
+    source: hosted
+    version: "3.0.1"
+  http:
+    dependency: "direct main"
+    description:
+      name: http
+      sha256: a2bbf9d017fcced29139daa8ed2bba4ece450ab222871df93ca9eec6f80c34ba
+      url: "https://pub.dev"
+    source: hosted
+    version: "1.2.0"
+  http_parser:
+    dependency: transitive
+    description:
+      name: http_parser
+      sha256: "2aa08ce0341cc9b354a498388e30986515406668dbcc4f7c950c3e715496693b"
+      url: "https://pub.dev"
+    source: hosted
+    version: "4.0.2"
+  markdown_editor:
+    dependency: "direct main"
+    description:
+      path: "."
+      ref: main
+      resolved-ref: "7c0b1a7d3f2e6a5b4c3d2e1f0a9b8c7d6e5f4a3b"
+      url: "https://github.com/example/markdown_editor.git"
+    source: git
+    version: "0.3.0"
+  sky_engine:
+    dependency: transitive
+    description: flutter
+    source: sdk
+    version: "0.0.99"
+sdks:
+  dart: ">=3.2.0 <4.0.0"
+  flutter: ">=3.16.0"
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeDart, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	toFind := []struct {
+		pattern      string
+		dependencies []dep.Dependency
+	}{
+		{"flutter_*", []dep.Dependency{
+			{Name: "flutter_lints", Constraint: "^3.0.1", Version: "3.0.1", IsDirect: true, IsDevOnly: true, ToolName: "pub"},
+			{Name: "flutter_test", IsDirect: true, IsDevOnly: true, ToolName: "pub", Source: "sdk+flutter"},
+		}},
+		{"http*", []dep.Dependency{
+			{Name: "http", Constraint: "^1.1.0", Version: "1.2.0", IsDirect: true, ToolName: "pub"},
+			{Name: "http_parser", Version: "4.0.2", ToolName: "pub"},
+		}},
+	}
+	for _, c := range toFind {
+		assert.ElementsMatch(t, c.dependencies, m.Find(c.pattern), c.pattern)
+	}
+
+	toGet := []struct {
+		name       string
+		dependency dep.Dependency
+		found      bool
+	}{
+		{name: "flutter", dependency: dep.Dependency{
+			Name: "flutter", IsDirect: true, ToolName: "pub", Source: "sdk+flutter",
+		}, found: true},
+		{name: "shared_models", dependency: dep.Dependency{
+			Name: "shared_models", IsDirect: true, ToolName: "pub", Source: "path+../shared_models",
+		}, found: true},
+		{name: "markdown_editor", dependency: dep.Dependency{
+			Name:     "markdown_editor",
+			Version:  "0.3.0",
+			IsDirect: true,
+			ToolName: "pub",
+			Source:   "git+https://github.com/example/markdown_editor.git#7c0b1a7d3f2e6a5b4c3d2e1f0a9b8c7d6e5f4a3b",
+		}, found: true},
+		{name: "private_pkg", dependency: dep.Dependency{
+			Name: "private_pkg", Constraint: "^2.0.0", IsDirect: true, ToolName: "pub",
+		}, found: true},
+		{name: "collection", dependency: dep.Dependency{
+			Name: "collection", Constraint: "any", IsDirect: true, ToolName: "pub",
+		}, found: true},
+		{name: "meta", dependency: dep.Dependency{
+			Name: "meta", Constraint: "1.11.0", ToolName: "pub",
+		}, found: true},
+		{name: "sky_engine", dependency: dep.Dependency{
+			Name: "sky_engine", Version: "0.0.99", ToolName: "pub", Source: "sdk+flutter",
+		}, found: true},
+		{name: "provider"},
+	}
+	for _, c := range toGet {
+		d, ok := m.Get(c.name)
+		assert.Equal(t, c.found, ok, c.name)
+		assert.Equal(t, c.dependency, d, c.name)
+	}
+
+	pm, ok := m.(dep.PlatformManager)
+	require.True(t, ok)
+	assert.Equal(t, []dep.Dependency{
+		{Name: "dart", Constraint: ">=3.2.0 <4.0.0", ToolName: "pub"},
+		{Name: "flutter", Constraint: ">=3.16.0", ToolName: "pub"},
+	}, pm.Platform())
+}
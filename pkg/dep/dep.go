@@ -2,40 +2,56 @@
 package dep
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/tidwall/jsonc"
 	"gopkg.in/yaml.v3"
-
-	"github.com/upsun/whatsun/internal/fsdir"
 )
 
 const (
-	ManagerTypeDotnet     = "dotnet"
-	ManagerTypeElixir     = "elixir"
-	ManagerTypeGo         = "go"
-	ManagerTypeJava       = "java"
-	ManagerTypeJavaScript = "js"
-	ManagerTypePHP        = "php"
-	ManagerTypePython     = "python"
-	ManagerTypeRuby       = "ruby"
-	ManagerTypeRust       = "rust"
+	ManagerTypeCpp           = "cpp"
+	ManagerTypeDart          = "dart"
+	ManagerTypeDocker        = "docker"
+	ManagerTypeDotnet        = "dotnet"
+	ManagerTypeElixir        = "elixir"
+	ManagerTypeGitHubActions = "github-actions"
+	ManagerTypeGo            = "go"
+	ManagerTypeHelm          = "helm"
+	ManagerTypeJava          = "java"
+	ManagerTypeJavaScript    = "js"
+	ManagerTypePHP           = "php"
+	ManagerTypePython        = "python"
+	ManagerTypeRuby          = "ruby"
+	ManagerTypeRust          = "rust"
+	ManagerTypeSwift         = "swift"
+	ManagerTypeTerraform     = "terraform"
 )
 
+// AllManagerTypes lists the built-in manager types. Use ManagerTypes to include managers added with Register.
 var AllManagerTypes = []string{
+	ManagerTypeCpp,
+	ManagerTypeDart,
+	ManagerTypeDocker,
 	ManagerTypeDotnet,
 	ManagerTypeElixir,
+	ManagerTypeGitHubActions,
 	ManagerTypeGo,
+	ManagerTypeHelm,
 	ManagerTypeJava,
 	ManagerTypeJavaScript,
 	ManagerTypePHP,
 	ManagerTypePython,
 	ManagerTypeRuby,
 	ManagerTypeRust,
+	ManagerTypeSwift,
+	ManagerTypeTerraform,
 }
 
 type Dependency struct {
@@ -46,13 +62,23 @@ type Dependency struct {
 	IsDirect   bool   // True if explicitly specified in manifest, false if transitive/from lock file only.
 	IsDevOnly  bool   // True if this is a development-only dependency.
 	ToolName   string // The external name of the tool that manages this dependency (e.g. "uv", "poetry", "composer").
+	Source     string // The source, if not a package registry (e.g. "git+https://example.com/repo.git", "path+../lib").
+	Registry   string // The package registry URL, if known (e.g. "https://registry.npmjs.org/").
+	Checksum   string // The checksum of the package from a lock file, as "algorithm:hex" (e.g. "sha256:4a5f…").
+	License    string // The declared license, usually an SPDX expression (e.g. "MIT OR Apache-2.0"), if known.
+	Category   string // The role of the dependency (e.g. "testing"), if known. It is set by Categorize.
 }
 
 type Manager interface {
 	// Init collects data for the manager: parsing files, etc.
 	// Implementations may be run multiple times: they should ensure they only read files once.
+	// An error is returned only if files cannot be read: problems in their contents are reported as diagnostics.
 	Init() error
 
+	// Diagnostics returns the problems found in files during Init, such as syntax errors. Dependencies are still
+	// found in the other files, or in the parts of files that could be read.
+	Diagnostics() []Diagnostic
+
 	// Get finds a specific dependency by name.
 	Get(name string) (Dependency, bool)
 
@@ -60,81 +86,169 @@ type Manager interface {
 	Find(pattern string) []Dependency
 }
 
-var managerFuncs = map[string]func(fs.FS, string) Manager{
-	ManagerTypeDotnet:     newDotnetManager,
-	ManagerTypeGo:         newGoManager,
-	ManagerTypeJava:       newJavaManager,
-	ManagerTypeJavaScript: newJSManager,
-	ManagerTypePHP:        newPHPManager,
-	ManagerTypePython:     newPythonManager,
-	ManagerTypeRuby:       newRubyManager,
-	ManagerTypeRust:       newRustManager,
-	ManagerTypeElixir:     newElixirManager,
+// PlatformManager is implemented by managers that declare platform requirements separately from packages, such as
+// Composer's "php" and "ext-*" requirements.
+type PlatformManager interface {
+	Manager
+
+	// Platform returns the platform requirements.
+	Platform() []Dependency
+}
+
+// LicenseManager is implemented by managers that read the license declared by the project itself, such as the
+// "license" field in package.json.
+type LicenseManager interface {
+	Manager
+
+	// License returns the declared license of the project, usually an SPDX expression, or an empty string.
+	License() string
+}
+
+// ManagerFactory creates a manager for the directory at a path in a file system. It should not read files: that is
+// done later, in Manager.Init.
+type ManagerFactory func(fsys fs.FS, path string) Manager
+
+var (
+	managerFuncsMu sync.RWMutex
+	managerFuncs   = map[string]ManagerFactory{
+		ManagerTypeCpp:           newCppManager,
+		ManagerTypeDart:          newDartManager,
+		ManagerTypeDocker:        newDockerManager,
+		ManagerTypeDotnet:        newDotnetManager,
+		ManagerTypeGitHubActions: newGitHubActionsManager,
+		ManagerTypeGo:            newGoManager,
+		ManagerTypeHelm:          newHelmManager,
+		ManagerTypeJava:          newJavaManager,
+		ManagerTypeJavaScript:    newJSManager,
+		ManagerTypePHP:           newPHPManager,
+		ManagerTypePython:        newPythonManager,
+		ManagerTypeRuby:          newRubyManager,
+		ManagerTypeRust:          newRustManager,
+		ManagerTypeSwift:         newSwiftManager,
+		ManagerTypeTerraform:     newTerraformManager,
+		ManagerTypeElixir:        newElixirManager,
+	}
+)
+
+// Register adds a manager type, such as one for an in-house package format. The manager is then available to
+// GetManager, to the CEL functions, and to the "deps" command.
+//
+// Register is intended to be called from an init function. It panics if the type is empty or already registered, or
+// if the factory is nil.
+func Register(managerType string, factory ManagerFactory) {
+	managerFuncsMu.Lock()
+	defer managerFuncsMu.Unlock()
+	if managerType == "" || factory == nil {
+		panic("dep: Register called with an empty manager type or a nil factory")
+	}
+	if _, exists := managerFuncs[managerType]; exists {
+		panic("dep: Register called twice for manager type " + managerType)
+	}
+	managerFuncs[managerType] = factory
+}
+
+// unregister removes a registered manager type, so that tests can undo Register.
+func unregister(managerType string) {
+	managerFuncsMu.Lock()
+	defer managerFuncsMu.Unlock()
+	delete(managerFuncs, managerType)
+}
+
+// ManagerTypes returns the types of all the available managers, built-in and registered, sorted by name.
+func ManagerTypes() []string {
+	managerFuncsMu.RLock()
+	defer managerFuncsMu.RUnlock()
+	types := make([]string, 0, len(managerFuncs))
+	for managerType := range managerFuncs {
+		types = append(types, managerType)
+	}
+	slices.Sort(types)
+	return types
 }
 
 // GetManager returns a dependency manager for the given type, filesystem and path.
 // The caller must then use Manager.Init to ensure files are parsed, when necessary.
 func GetManager(managerType string, fsys fs.FS, path string) (Manager, error) {
-	if managerFunc, ok := managerFuncs[managerType]; ok {
-		return managerFunc(fsys, path), nil
+	managerFuncsMu.RLock()
+	managerFunc, ok := managerFuncs[managerType]
+	managerFuncsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("manager type not supported: %s", managerType)
 	}
-	return nil, fmt.Errorf("manager type not supported: %s", managerType)
+	return managerFunc(fsys, path), nil
 }
 
+// parseJSON decodes a JSON file. A syntax error is returned as a *Diagnostic.
 func parseJSON(fsys fs.FS, path, filename string, dest any) error {
-	f, err := fsys.Open(filepath.Join(path, filename))
+	b, err := fs.ReadFile(fsys, filepath.Join(path, filename))
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	if err := json.NewDecoder(f).Decode(dest); err != nil {
-		return fmt.Errorf("failed to parse %s as JSON: %w", filepath.Join(path, filename), err)
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(dest); err != nil {
+		return newDiagnostic(filepath.Join(path, filename), b, fmt.Errorf("failed to parse as JSON: %w", err))
 	}
 	return nil
 }
 
+// parseJSONC decodes a JSON file which may contain comments and trailing commas. A syntax error is returned as a
+// *Diagnostic.
 func parseJSONC(fsys fs.FS, path, filename string, dest any) error {
 	b, err := fs.ReadFile(fsys, filepath.Join(path, filename))
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(jsonc.ToJSONInPlace(b), dest); err != nil {
-		return fmt.Errorf("failed to parse %s as JSONC: %w", filepath.Join(path, filename), err)
+	// Comments are replaced by spaces, so offsets are preserved.
+	if err := json.Unmarshal(jsonc.ToJSON(b), dest); err != nil {
+		return newDiagnostic(filepath.Join(path, filename), b, fmt.Errorf("failed to parse as JSONC: %w", err))
 	}
 	return nil
 }
 
+// parseYAML decodes a YAML file. A syntax error is returned as a *Diagnostic.
 func parseYAML(fsys fs.FS, path, filename string, dest any) error {
-	f, err := fsys.Open(filepath.Join(path, filename))
+	b, err := fs.ReadFile(fsys, filepath.Join(path, filename))
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	if err := yaml.NewDecoder(f).Decode(dest); err != nil {
-		return fmt.Errorf("failed to parse %s as YAML: %w", filepath.Join(path, filename), err)
+	if err := yaml.Unmarshal(b, dest); err != nil {
+		return newDiagnostic(filepath.Join(path, filename), b, fmt.Errorf("failed to parse as YAML: %w", err))
 	}
 	return nil
 }
 
-var managerCache sync.Map
-
-// GetCachedManager returns a cached and initialized dep.Manager for the given filesystem directory.
-func GetCachedManager(managerType string, fsd fsdir.FSDir) (Manager, error) {
-	cacheKey := struct {
-		managerType string
-		fsID        uintptr
-		path        string
-	}{managerType, fsd.ID(), fsd.Path()}
-	if manager, ok := managerCache.Load(cacheKey); ok {
-		return manager.(Manager), nil //nolint:errcheck // the cached value is known
+// joinLicenses combines a list of alternative licenses, such as the "license" array in composer.json, into one
+// SPDX expression.
+func joinLicenses(licenses []string) string {
+	if len(licenses) == 1 {
+		return licenses[0]
 	}
-	m, err := GetManager(managerType, fsd.FS(), cacheKey.path)
-	if err != nil {
-		return nil, err
+	parts := make([]string, 0, len(licenses))
+	for _, l := range licenses {
+		if strings.Contains(l, " ") {
+			l = "(" + l + ")"
+		}
+		parts = append(parts, l)
 	}
-	managerCache.Store(cacheKey, m)
-	if err := m.Init(); err != nil {
-		return nil, err
+	return strings.Join(parts, " OR ")
+}
+
+// stripLineComment removes a comment, starting with any of the markers, from a line of code.
+// Markers inside double-quoted strings are ignored.
+func stripLineComment(line string, markers ...string) string {
+	var inString bool
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '"':
+			inString = !inString
+		case line[i] == '\\' && inString:
+			i++
+		case !inString:
+			for _, marker := range markers {
+				if strings.HasPrefix(line[i:], marker) {
+					return line[:i]
+				}
+			}
+		}
 	}
-	return m, nil
+	return line
 }
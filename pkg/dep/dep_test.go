@@ -0,0 +1,85 @@
+package dep_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+// linesManager is a custom manager, reading one dependency name per line from a "deps.txt" file.
+type linesManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     []dep.Dependency
+}
+
+func (m *linesManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		var b []byte
+		b, err = fs.ReadFile(m.fsys, filepath.Join(m.path, "deps.txt"))
+		for _, name := range strings.Fields(string(b)) {
+			m.deps = append(m.deps, dep.Dependency{Name: name, IsDirect: true, ToolName: "lines"})
+		}
+	})
+	return err
+}
+
+func (m *linesManager) Diagnostics() []dep.Diagnostic {
+	return nil
+}
+
+func (m *linesManager) Get(name string) (dep.Dependency, bool) {
+	for _, d := range m.deps {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return dep.Dependency{}, false
+}
+
+func (m *linesManager) Find(pattern string) []dep.Dependency {
+	var deps []dep.Dependency
+	for _, d := range m.deps {
+		if wildcard.Match(pattern, d.Name) {
+			deps = append(deps, d)
+		}
+	}
+	return deps
+}
+
+func TestRegister(t *testing.T) {
+	const managerType = "test-lines"
+	dep.Register(managerType, func(fsys fs.FS, path string) dep.Manager {
+		return &linesManager{fsys: fsys, path: path}
+	})
+	t.Cleanup(func() { dep.Unregister(managerType) })
+	assert.Contains(t, dep.ManagerTypes(), managerType)
+	assert.IsIncreasing(t, dep.ManagerTypes())
+
+	assert.Panics(t, func() {
+		dep.Register(managerType, func(fs.FS, string) dep.Manager { return nil })
+	})
+	assert.Panics(t, func() {
+		dep.Register(dep.ManagerTypePHP, func(fs.FS, string) dep.Manager { return nil })
+	})
+
+	fsys := fstest.MapFS{"app/deps.txt": {Data: []byte("foo\nbar\n")}}
+	m, err := dep.GetManager(managerType, fsys, "app")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+	d, ok := m.Get("bar")
+	assert.True(t, ok)
+	assert.Equal(t, dep.Dependency{Name: "bar", IsDirect: true, ToolName: "lines"}, d)
+}
@@ -0,0 +1,96 @@
+package dep
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/mod/modfile"
+)
+
+// Diagnostic describes a problem found in a file, such as a syntax error.
+//
+// Managers report diagnostics instead of failing, so that dependencies are still found in their other files.
+type Diagnostic struct {
+	File    string // The path of the file.
+	Line    int    // The line number, starting at 1, or 0 if it is not known.
+	Message string // A description of the problem.
+}
+
+func (d *Diagnostic) Error() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.File, d.Message)
+}
+
+var yamlLinePattern = regexp.MustCompile(`\bline (\d+):`)
+
+// newDiagnostic creates a diagnostic for an error found in the content of a file. The line number is read from the
+// error if possible, or found from the offset of a JSON error in the content (which may be nil). An unexpected end of
+// file is reported on the last line.
+func newDiagnostic(file string, content []byte, err error) *Diagnostic {
+	d := &Diagnostic{File: file, Message: err.Error()}
+	var (
+		jsonSyntaxErr *json.SyntaxError
+		jsonTypeErr   *json.UnmarshalTypeError
+		xmlErr        *xml.SyntaxError
+		tomlErr       toml.ParseError
+		modfileErrs   modfile.ErrorList
+	)
+	switch {
+	case errors.As(err, &jsonSyntaxErr):
+		d.Line = lineAtOffset(content, jsonSyntaxErr.Offset)
+	case errors.As(err, &jsonTypeErr):
+		d.Line = lineAtOffset(content, jsonTypeErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		d.Line = lineAtOffset(content, int64(len(content)))
+	case errors.As(err, &xmlErr):
+		d.Line = xmlErr.Line
+	case errors.As(err, &tomlErr):
+		d.Line = tomlErr.Position.Line
+	case errors.As(err, &modfileErrs) && len(modfileErrs) > 0:
+		// The message of a modfile error already starts with the file name and line.
+		d.Line, d.Message = modfileErrs[0].Pos.Line, modfileErrs[0].Err.Error()
+	default:
+		if m := yamlLinePattern.FindStringSubmatch(d.Message); m != nil {
+			d.Line, _ = strconv.Atoi(m[1])
+		}
+	}
+	return d
+}
+
+// lineAtOffset returns the line number of a byte offset in content, or 0 if the content is not known.
+func lineAtOffset(content []byte, offset int64) int {
+	if content == nil || offset < 0 {
+		return 0
+	}
+	offset = min(offset, int64(len(content)))
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// diagnostics collects the diagnostics of a manager. It can be embedded to implement Manager.Diagnostics.
+type diagnostics struct {
+	list []Diagnostic
+}
+
+// Diagnostics returns the problems found in files while initializing the manager.
+func (d *diagnostics) Diagnostics() []Diagnostic {
+	return d.list
+}
+
+// report records err as a diagnostic if it describes a problem in a file, and returns any other error.
+func (d *diagnostics) report(err error) error {
+	var diag *Diagnostic
+	if errors.As(err, &diag) {
+		d.list = append(d.list, *diag)
+		return nil
+	}
+	return err
+}
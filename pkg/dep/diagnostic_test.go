@@ -0,0 +1,134 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestDiagnostics(t *testing.T) {
+	cases := []struct {
+		name        string
+		managerType string
+		fsys        fstest.MapFS
+		path        string
+		found       []string
+		diagnostics []dep.Diagnostic
+	}{
+		{
+			name:        "broken composer.lock",
+			managerType: dep.ManagerTypePHP,
+			fsys: fstest.MapFS{
+				"app/composer.json": {Data: []byte(`{"require": {"symfony/console": "^7.0"}}`)},
+				"app/composer.lock": {Data: []byte("{\n  \"packages\": [\n    {\"name\": \"a/b\",}\n  ]\n}")},
+			},
+			path:  "app",
+			found: []string{"symfony/console"},
+			diagnostics: []dep.Diagnostic{{
+				File:    "app/composer.lock",
+				Line:    3,
+				Message: "failed to parse as JSON: invalid character '}' looking for beginning of object key string",
+			}},
+		},
+		{
+			name:        "broken package.json with a valid lock file",
+			managerType: dep.ManagerTypeJavaScript,
+			fsys: fstest.MapFS{
+				"package.json": {Data: []byte("{\n  \"dependencies\": {\n    \"a\": \"^1.0\"\n  \n")},
+				"package-lock.json": {Data: []byte(
+					`{"lockfileVersion": 3, "packages": {"node_modules/a": {"version": "1.2.0"}}}`)},
+			},
+			path:  ".",
+			found: []string{"a"},
+			diagnostics: []dep.Diagnostic{{
+				File:    "package.json",
+				Line:    5,
+				Message: "failed to parse as JSON: unexpected EOF",
+			}},
+		},
+		{
+			name:        "broken pnpm-lock.yaml",
+			managerType: dep.ManagerTypeJavaScript,
+			fsys: fstest.MapFS{
+				"package.json":   {Data: []byte(`{"dependencies": {"a": "^1.0"}}`)},
+				"pnpm-lock.yaml": {Data: []byte("lockfileVersion: '9.0'\npackages:\n  a@1.2.0: [\n")},
+			},
+			path:  ".",
+			found: []string{"a"},
+			diagnostics: []dep.Diagnostic{{
+				File:    "pnpm-lock.yaml",
+				Line:    3,
+				Message: "failed to parse as YAML: yaml: line 3: did not find expected node content",
+			}},
+		},
+		{
+			name:        "broken go.mod",
+			managerType: dep.ManagerTypeGo,
+			fsys: fstest.MapFS{
+				"go.mod": {Data: []byte("module example.com/m\n\ngo 1.24\n\nrequier example.com/a v1.0.0\n")},
+			},
+			path: ".",
+			diagnostics: []dep.Diagnostic{{
+				File:    "go.mod",
+				Line:    5,
+				Message: `unknown directive: requier`,
+			}},
+		},
+		{
+			name:        "broken Cargo.lock",
+			managerType: dep.ManagerTypeRust,
+			fsys: fstest.MapFS{
+				"Cargo.toml": {Data: []byte("[package]\nname = \"app\"\n\n[dependencies]\nserde = \"1.0\"\n")},
+				"Cargo.lock": {Data: []byte("[[package]]\nname = \"serde\"\nversion = 1.0.0\"\n")},
+			},
+			path:  ".",
+			found: []string{"serde"},
+			diagnostics: []dep.Diagnostic{{
+				File:    "Cargo.lock",
+				Line:    3,
+				Message: `toml: line 3 (last key "package.version"): Invalid float value: "1.0.0"`,
+			}},
+		},
+		{
+			name:        "one broken project file",
+			managerType: dep.ManagerTypeDotnet,
+			fsys: fstest.MapFS{
+				"a.csproj": {Data: []byte("<Project>\n  <ItemGroup>\n    <PackageReference Include=\"Serilog\" />\n" +
+					"  </ItemGroup>\n</Project>\n")},
+				"b.csproj": {Data: []byte("<Project>\n  <ItemGroup>\n  </ItemGroups>\n</Project>\n")},
+			},
+			path:  ".",
+			found: []string{"Serilog"},
+			diagnostics: []dep.Diagnostic{{
+				File:    "b.csproj",
+				Line:    3,
+				Message: "failed to parse as XML: XML syntax error on line 3: element <ItemGroup> closed by </ItemGroups>",
+			}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, err := dep.GetManager(c.managerType, c.fsys, c.path)
+			require.NoError(t, err)
+			require.NoError(t, m.Init())
+
+			var found []string
+			for _, d := range m.Find("*") {
+				found = append(found, d.Name)
+			}
+			assert.ElementsMatch(t, c.found, found)
+			assert.Equal(t, c.diagnostics, m.Diagnostics())
+		})
+	}
+}
+
+func TestDiagnostic_Error(t *testing.T) {
+	d := &dep.Diagnostic{File: "composer.json", Line: 3, Message: "invalid"}
+	assert.Equal(t, "composer.json:3: invalid", d.Error())
+	d.Line = 0
+	assert.Equal(t, "composer.json: invalid", d.Error())
+}
@@ -0,0 +1,96 @@
+package dep
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// ChangeKind describes how a dependency changed between two revisions.
+type ChangeKind string
+
+const (
+	ChangeAdded      ChangeKind = "added"
+	ChangeRemoved    ChangeKind = "removed"
+	ChangeUpgraded   ChangeKind = "upgraded"   // The resolved version increased.
+	ChangeDowngraded ChangeKind = "downgraded" // The resolved version decreased.
+	ChangeModified   ChangeKind = "modified"   // The constraint changed, or versions changed and cannot be compared.
+)
+
+// Change is a difference in a dependency between two revisions.
+type Change struct {
+	Path        string
+	ManagerType string
+	Name        string
+	Kind        ChangeKind
+	Before      Dependency // The dependency before, unless it was added.
+	After       Dependency // The dependency after, unless it was removed.
+}
+
+// DiffDependencies compares the dependencies found in two revisions, matching them by path, manager type and name.
+// Changes are sorted by path, manager type and name.
+func DiffDependencies(before, after []Usage) []Change {
+	type key struct{ path, managerType, name string }
+	index := func(usages []Usage) map[key]Dependency {
+		m := make(map[key]Dependency, len(usages))
+		for _, u := range usages {
+			k := key{u.Path, u.ManagerType, u.Dependency.Name}
+			if _, ok := m[k]; !ok {
+				m[k] = u.Dependency
+			}
+		}
+		return m
+	}
+	beforeDeps, afterDeps := index(before), index(after)
+
+	var changes []Change
+	for k, b := range beforeDeps {
+		c := Change{Path: k.path, ManagerType: k.managerType, Name: k.name, Before: b}
+		a, ok := afterDeps[k]
+		if !ok {
+			c.Kind = ChangeRemoved
+			changes = append(changes, c)
+			continue
+		}
+		c.After = a
+		if c.Kind = compareDependencies(b, a); c.Kind != "" {
+			changes = append(changes, c)
+		}
+	}
+	for k, a := range afterDeps {
+		if _, ok := beforeDeps[k]; !ok {
+			changes = append(changes, Change{Path: k.path, ManagerType: k.managerType, Name: k.name, Kind: ChangeAdded,
+				After: a})
+		}
+	}
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Or(
+			strings.Compare(a.Path, b.Path),
+			strings.Compare(a.ManagerType, b.ManagerType),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return changes
+}
+
+// compareDependencies returns the kind of change between two versions of a dependency, or an empty string if the
+// version and constraint are the same. Equal versions written differently (e.g. "v1.2" and "1.2.0") are the same.
+func compareDependencies(before, after Dependency) ChangeKind {
+	sameVersion := before.Version == after.Version
+	if !sameVersion && before.Version != "" && after.Version != "" {
+		c, err := CompareVersions(before.Version, after.Version)
+		switch {
+		case err != nil:
+			return ChangeModified
+		case c < 0:
+			return ChangeUpgraded
+		case c > 0:
+			return ChangeDowngraded
+		}
+		sameVersion = true
+	}
+	if !sameVersion || before.Constraint != after.Constraint {
+		return ChangeModified
+	}
+	return ""
+}
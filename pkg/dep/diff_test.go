@@ -0,0 +1,49 @@
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestDiffDependencies(t *testing.T) {
+	php := func(name, constraint, version string) dep.Usage {
+		return dep.Usage{Path: "api", ManagerType: "php", Dependency: dep.Dependency{
+			Name: name, Constraint: constraint, Version: version, ToolName: "composer"}}
+	}
+	before := []dep.Usage{
+		php("a/unchanged", "^1.0", "1.0.0"),
+		php("a/equivalent", "^1.0", "v1.0"),
+		php("a/upgraded", "^1.0", "1.0.0"),
+		php("a/downgraded", "^1.0", "1.2.0"),
+		php("a/constraint", "^1.0", ""),
+		php("a/removed", "^1.0", "1.0.0"),
+		php("a/branch", "dev-main", "dev-main"),
+	}
+	after := []dep.Usage{
+		php("a/unchanged", "^1.0", "1.0.0"),
+		php("a/equivalent", "^1.0", "1.0.0"),
+		php("a/upgraded", "^1.0", "1.1.0"),
+		php("a/downgraded", "^1.0", "1.1.0"),
+		php("a/constraint", "^2.0", ""),
+		php("a/branch", "dev-main", "dev-feature"),
+		php("a/added", "^3.0", "3.0.0"),
+		{Path: "web", ManagerType: "js", Dependency: dep.Dependency{Name: "a/removed", Version: "1.0.0"}},
+	}
+
+	assert.Equal(t, []dep.Change{
+		{Path: "api", ManagerType: "php", Name: "a/added", Kind: dep.ChangeAdded, After: after[6].Dependency},
+		{Path: "api", ManagerType: "php", Name: "a/branch", Kind: dep.ChangeModified,
+			Before: before[6].Dependency, After: after[5].Dependency},
+		{Path: "api", ManagerType: "php", Name: "a/constraint", Kind: dep.ChangeModified,
+			Before: before[4].Dependency, After: after[4].Dependency},
+		{Path: "api", ManagerType: "php", Name: "a/downgraded", Kind: dep.ChangeDowngraded,
+			Before: before[3].Dependency, After: after[3].Dependency},
+		{Path: "api", ManagerType: "php", Name: "a/removed", Kind: dep.ChangeRemoved, Before: before[5].Dependency},
+		{Path: "api", ManagerType: "php", Name: "a/upgraded", Kind: dep.ChangeUpgraded,
+			Before: before[2].Dependency, After: after[2].Dependency},
+		{Path: "web", ManagerType: "js", Name: "a/removed", Kind: dep.ChangeAdded, After: after[7].Dependency},
+	}, dep.DiffDependencies(before, after))
+}
@@ -0,0 +1,234 @@
+package dep
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+// dockerManager reads container images from Dockerfile "FROM" instructions and Compose "image" entries.
+type dockerManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	diagnostics
+}
+
+func newDockerManager(fsys fs.FS, path string) Manager {
+	return &dockerManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *dockerManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+var (
+	dockerfilePatterns = []string{"Dockerfile", "Dockerfile.*", "*.Dockerfile", "*.dockerfile", "Containerfile"}
+	composePatterns    = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml",
+		"compose.*.yaml", "compose.*.yml", "docker-compose.*.yaml", "docker-compose.*.yml"}
+)
+
+func (m *dockerManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
+	dockerfiles, err := m.glob(dockerfilePatterns)
+	if err != nil {
+		return err
+	}
+	for _, filename := range dockerfiles {
+		f, err := m.fsys.Open(filename)
+		if err != nil {
+			return err
+		}
+		images, err := parseDockerfile(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		for _, image := range images {
+			m.addImage(image, "docker")
+		}
+	}
+
+	composeFiles, err := m.glob(composePatterns)
+	if err != nil {
+		return err
+	}
+	for _, filename := range composeFiles {
+		var compose struct {
+			Services map[string]struct {
+				Image string `yaml:"image"`
+			} `yaml:"services"`
+		}
+		err := parseYAML(m.fsys, filepath.Dir(filename), filepath.Base(filename), &compose)
+		if m.report(err) != nil {
+			return err
+		}
+		for _, service := range compose.Services {
+			if service.Image != "" {
+				m.addImage(expandVariables(service.Image, nil), "compose")
+			}
+		}
+	}
+
+	return nil
+}
+
+// glob finds files matching any of the patterns, in a stable order, without duplicates.
+func (m *dockerManager) glob(patterns []string) ([]string, error) {
+	var filenames []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(m.fsys, filepath.Join(m.path, pattern))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !slices.Contains(filenames, match) {
+				filenames = append(filenames, match)
+			}
+		}
+	}
+	return filenames, nil
+}
+
+// addImage adds an image reference as a dependency. The first reference to an image takes precedence.
+func (m *dockerManager) addImage(ref, toolName string) {
+	image, ok := parseImageReference(ref)
+	if !ok {
+		return
+	}
+	if _, exists := m.deps[image.name]; exists {
+		return
+	}
+	version := image.tag
+	if version == "" {
+		version = image.digest
+	}
+	m.deps[image.name] = Dependency{
+		Name:     image.name,
+		Version:  version,
+		IsDirect: true,
+		ToolName: toolName,
+	}
+}
+
+func (m *dockerManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *dockerManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+var (
+	dockerFromPatt = regexp.MustCompile(`(?i)^FROM\s+(?:--\S+\s+)*(\S+)(?:\s+AS\s+(\S+))?`)
+	dockerArgPatt  = regexp.MustCompile(`(?i)^ARG\s+(\w+)(?:=(\S*))?`)
+)
+
+// parseDockerfile returns the base image references from a Dockerfile, skipping references to earlier build
+// stages. Variables are expanded using the default values of ARG instructions.
+// See: https://docs.docker.com/reference/dockerfile/#from
+func parseDockerfile(r io.Reader) ([]string, error) {
+	var images []string
+	stages := make(map[string]bool)
+	args := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if matches := dockerArgPatt.FindStringSubmatch(line); matches != nil {
+			if _, ok := args[matches[1]]; !ok {
+				args[matches[1]] = strings.Trim(matches[2], `"'`)
+			}
+			continue
+		}
+		matches := dockerFromPatt.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		if matches[2] != "" {
+			stages[strings.ToLower(matches[2])] = true
+		}
+		image := expandVariables(matches[1], args)
+		if image == "scratch" || stages[strings.ToLower(image)] {
+			continue
+		}
+		images = append(images, image)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+var variablePatt = regexp.MustCompile(`\$(?:\{(\w+)(?:(:?[-+])([^}]*))?\}|(\w+))`)
+
+// expandVariables expands variable references using the given values, with the shell's semantics for defaults and
+// alternatives: "$VAR" and "${VAR}" are replaced with the value, "${VAR:-default}" with the default if VAR is unset
+// or empty, and "${VAR:+alternative}" with the alternative if VAR is set and not empty, or otherwise nothing. Without
+// the colon, as in "${VAR-default}" or "${VAR+alternative}", an empty value counts as set.
+func expandVariables(s string, values map[string]string) string {
+	return variablePatt.ReplaceAllStringFunc(s, func(ref string) string {
+		matches := variablePatt.FindStringSubmatch(ref)
+		name, op, word := matches[1]+matches[4], matches[2], matches[3]
+		v, ok := values[name]
+		set := ok && (v != "" || !strings.HasPrefix(op, ":"))
+		switch {
+		case strings.HasSuffix(op, "+"):
+			if set {
+				return word
+			}
+			return ""
+		case set:
+			return v
+		}
+		return word
+	})
+}
+
+type imageReference struct {
+	name   string
+	tag    string
+	digest string
+}
+
+// parseImageReference parses a reference in the format "[registry/]name[:tag][@digest]". Images in the official
+// Docker Hub library are normalized to their short name, e.g. "docker.io/library/postgres" to "postgres".
+func parseImageReference(ref string) (imageReference, bool) {
+	var image imageReference
+	ref, image.digest, _ = strings.Cut(ref, "@")
+	image.name = ref
+	// The tag is after the last colon, unless that colon is part of a registry host with a port.
+	if i := strings.LastIndex(ref, ":"); i != -1 && !strings.Contains(ref[i:], "/") {
+		image.name, image.tag = ref[:i], ref[i+1:]
+	}
+	image.name = strings.TrimPrefix(image.name, "docker.io/")
+	image.name = strings.TrimPrefix(image.name, "index.docker.io/")
+	image.name = strings.TrimPrefix(image.name, "library/")
+	if image.name == "" || strings.ContainsAny(image.name, "${}") {
+		return imageReference{}, false
+	}
+	return image, true
+}
@@ -0,0 +1,124 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestDocker(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Dockerfile": { //nolint:lll
+			Data: []byte(`# syntax=docker/dockerfile:1
+ARG NODE_VERSION=20.11
+FROM --platform=$BUILDPLATFORM node:${NODE_VERSION}-alpine AS build
+WORKDIR /app
+COPY . .
+RUN npm ci && npm run build
+
+FROM build AS test
+RUN npm test
+
+FROM docker.io/library/nginx:1.25@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac
+COPY --from=build /app/dist /usr/share/nginx/html
+`),
+		},
+		"worker.Dockerfile": {Data: []byte(`FROM ghcr.io/acme/worker-base@sha256:0f6e9d2e5c2f0a1c8b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c
+FROM scratch
+`)},
+		"python.Dockerfile": {Data: []byte(`ARG SLIM=1
+ARG DEBUG
+FROM python:3.12${SLIM:+-slim}${DEBUG:+-debug}
+`)},
+		"compose.yaml": {Data: []byte(`services:
+  app:
+    build: .
+    depends_on: [db, cache]
+  db:
+    image: postgres:${POSTGRES_VERSION:-16}
+  cache:
+    image: redis
+  search:
+    image: localhost:5000/opensearch:2.11.1
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeDocker, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "node", Version: "20.11-alpine", IsDirect: true, ToolName: "docker"},
+		{Name: "nginx", Version: "1.25", IsDirect: true, ToolName: "docker"},
+		{
+			Name:     "ghcr.io/acme/worker-base",
+			Version:  "sha256:0f6e9d2e5c2f0a1c8b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d7c6b5a4f3e2d1c",
+			IsDirect: true,
+			ToolName: "docker",
+		},
+		{Name: "python", Version: "3.12-slim", IsDirect: true, ToolName: "docker"},
+		{Name: "postgres", Version: "16", IsDirect: true, ToolName: "compose"},
+		{Name: "redis", IsDirect: true, ToolName: "compose"},
+		{Name: "localhost:5000/opensearch", Version: "2.11.1", IsDirect: true, ToolName: "compose"},
+	}, m.Find("*"))
+}
+
+func TestGitHubActions(t *testing.T) {
+	fsys := fstest.MapFS{
+		".github/workflows/ci.yml": {Data: []byte(`name: CI
+on: [push]
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@b4ffde65f46336ab88eb53be808477a3936bae11 # v4.1.1
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+      - uses: ./.github/actions/lint
+      - uses: docker://alpine:3.19
+      - run: go test ./...
+  deploy:
+    uses: acme/workflows/.github/workflows/deploy.yml@main
+`)},
+		".github/actions/lint/action.yml": {Data: []byte(`name: Lint
+runs:
+  using: composite
+  steps:
+    - uses: golangci/golangci-lint-action@v6
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeGitHubActions, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.ElementsMatch(t, []dep.Dependency{
+		{
+			Vendor:   "actions",
+			Name:     "actions/checkout",
+			Version:  "b4ffde65f46336ab88eb53be808477a3936bae11",
+			IsDirect: true,
+			ToolName: "github-actions",
+		},
+		{Vendor: "actions", Name: "actions/setup-go", Version: "v5", IsDirect: true, ToolName: "github-actions"},
+		{
+			Vendor:   "acme",
+			Name:     "acme/workflows/.github/workflows/deploy.yml",
+			Version:  "main",
+			IsDirect: true,
+			ToolName: "github-actions",
+		},
+		{
+			Vendor:   "golangci",
+			Name:     "golangci/golangci-lint-action",
+			Version:  "v6",
+			IsDirect: true,
+			ToolName: "github-actions",
+		},
+	}, m.Find("*"))
+}
@@ -3,8 +3,10 @@ package dep
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io/fs"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -15,23 +17,63 @@ type dotnetManager struct {
 	fsys fs.FS
 	path string
 
-	csprojFiles []csprojFile
-	lockFile    packagesLock
+	direct   []Dependency
+	lockFile packagesLock
 
 	initOnce sync.Once
+	diagnostics
 }
 
-type csprojFile struct {
-	XMLName       xml.Name `xml:"Project"`
-	PropertyGroup []struct {
-		TargetFramework string `xml:"TargetFramework"`
+// msbuildProject represents the parts of an MSBuild file that are relevant to dependencies.
+// This covers project files (.csproj, .fsproj, .vbproj), Directory.Build.props and Directory.Packages.props.
+type msbuildProject struct {
+	XMLName        xml.Name `xml:"Project"`
+	PropertyGroups []struct {
+		Properties []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
 	} `xml:"PropertyGroup"`
 	ItemGroups []struct {
-		PackageReferences []struct {
-			Include string `xml:"Include,attr"`
-			Version string `xml:"Version,attr"`
-		} `xml:"PackageReference"`
+		PackageReferences       []msbuildPackageItem `xml:"PackageReference"`
+		PackageVersions         []msbuildPackageItem `xml:"PackageVersion"`
+		GlobalPackageReferences []msbuildPackageItem `xml:"GlobalPackageReference"`
 	} `xml:"ItemGroup"`
+	Imports []struct {
+		Project string `xml:"Project,attr"`
+	} `xml:"Import"`
+}
+
+type msbuildPackageItem struct {
+	Include         string `xml:"Include,attr"`
+	Update          string `xml:"Update,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionElement  string `xml:"Version"`
+	VersionOverride string `xml:"VersionOverride,attr"`
+}
+
+func (i msbuildPackageItem) name() string {
+	if i.Include != "" {
+		return i.Include
+	}
+	return i.Update
+}
+
+func (i msbuildPackageItem) version() string {
+	if i.Version != "" {
+		return i.Version
+	}
+	return strings.TrimSpace(i.VersionElement)
+}
+
+// packagesConfig is the legacy NuGet packages.config format.
+type packagesConfig struct {
+	XMLName  xml.Name `xml:"packages"`
+	Packages []struct {
+		ID                    string `xml:"id,attr"`
+		Version               string `xml:"version,attr"`
+		DevelopmentDependency bool   `xml:"developmentDependency,attr"`
+	} `xml:"package"`
 }
 
 type packagesLock struct {
@@ -42,6 +84,8 @@ type packagesLock struct {
 	} `json:"targets"`
 }
 
+var dotnetProjectExtensions = []string{".csproj", ".fsproj", ".vbproj"}
+
 func newDotnetManager(fsys fs.FS, path string) Manager {
 	return &dotnetManager{
 		fsys: fsys,
@@ -58,67 +102,242 @@ func (m *dotnetManager) Init() error {
 }
 
 func (m *dotnetManager) init() error {
-	// Find all .csproj files in the directory
 	entries, err := fs.ReadDir(m.fsys, m.path)
 	if err != nil {
 		return err
 	}
 
+	var projects []*msbuildProject
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".csproj") {
-			var csproj csprojFile
-			if err := m.parseCSProj(entry.Name(), &csproj); err != nil {
-				// Continue with other files even if one fails
-				continue
+		if entry.IsDir() || !hasAnySuffix(entry.Name(), dotnetProjectExtensions) {
+			continue
+		}
+		var project msbuildProject
+		if err := parseXML(m.fsys, m.path, entry.Name(), &project); err != nil {
+			// Continue with other files even if one fails
+			if m.report(err) != nil {
+				return err
 			}
-			m.csprojFiles = append(m.csprojFiles, csproj)
+			continue
 		}
+		projects = append(projects, &project)
 	}
 
 	// Try to parse packages.lock.json if it exists
 	if err := parseJSON(m.fsys, m.path, "packages.lock.json", &m.lockFile); err != nil {
-		if !errors.Is(err, fs.ErrNotExist) {
+		if !errors.Is(err, fs.ErrNotExist) && m.report(err) != nil {
 			return err
 		}
 	}
 
+	if len(projects) > 0 {
+		if err := m.collectProjectDeps(projects); err != nil {
+			return err
+		}
+	}
+
+	return m.collectPackagesConfigDeps()
+}
+
+// collectProjectDeps collects PackageReference items from project files, resolving versions from inherited
+// Directory.Build.props files and from Central Package Management (Directory.Packages.props).
+func (m *dotnetManager) collectProjectDeps(projects []*msbuildProject) error {
+	// A props file that cannot be parsed is skipped, along with the files it would import.
+	buildProps, err := m.findBuildProps()
+	if m.report(err) != nil {
+		return err
+	}
+	packagesProps, err := m.findProps("Directory.Packages.props")
+	if m.report(err) != nil {
+		return err
+	}
+
+	// Properties are evaluated in import order: Directory.Build.props from the outermost directory first.
+	properties := make(map[string]string)
+	for i := len(buildProps) - 1; i >= 0; i-- {
+		buildProps[i].addProperties(properties)
+	}
+
+	centralVersions := make(map[string]string)
+	if packagesProps != nil {
+		packagesProps.addProperties(properties)
+		for _, ig := range packagesProps.ItemGroups {
+			for _, pv := range ig.PackageVersions {
+				centralVersions[pv.name()] = pv.version()
+			}
+			for _, gr := range ig.GlobalPackageReferences {
+				// Global package references are intended for development tools such as analyzers.
+				m.addDirect(gr.name(), expandMSBuildProperties(gr.version(), properties), true, "dotnet")
+			}
+		}
+	}
+
+	// Package references in Directory.Build.props are inherited by every project.
+	var references []msbuildPackageItem
+	for i := len(buildProps) - 1; i >= 0; i-- {
+		for _, ig := range buildProps[i].ItemGroups {
+			references = append(references, ig.PackageReferences...)
+		}
+	}
+	for _, project := range projects {
+		project.addProperties(properties)
+		for _, ig := range project.ItemGroups {
+			references = append(references, ig.PackageReferences...)
+		}
+	}
+
+	for _, ref := range references {
+		if ref.Include == "" {
+			continue
+		}
+		constraint := ref.VersionOverride
+		if constraint == "" {
+			constraint = ref.version()
+		}
+		if constraint == "" {
+			constraint = centralVersions[ref.Include]
+		}
+		m.addDirect(ref.Include, expandMSBuildProperties(constraint, properties), false, "dotnet")
+	}
+	return nil
+}
+
+// collectPackagesConfigDeps collects dependencies from a legacy packages.config file.
+func (m *dotnetManager) collectPackagesConfigDeps() error {
+	var config packagesConfig
+	if err := parseXML(m.fsys, m.path, "packages.config", &config); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+	for _, p := range config.Packages {
+		m.addDirect(p.ID, p.Version, p.DevelopmentDependency, "nuget")
+	}
 	return nil
 }
 
-func (m *dotnetManager) parseCSProj(filename string, dest *csprojFile) error {
-	f, err := m.fsys.Open(filepath.Join(m.path, filename))
+func (m *dotnetManager) addDirect(name, constraint string, devOnly bool, toolName string) {
+	for _, d := range m.direct {
+		if d.Name == name {
+			return
+		}
+	}
+	version := m.getLockedVersion(name)
+	if version == "" && m.isSpecificVersion(constraint) {
+		version = constraint
+	}
+	m.direct = append(m.direct, Dependency{
+		Name:       name,
+		Constraint: constraint,
+		Version:    version,
+		IsDirect:   true,
+		IsDevOnly:  devOnly,
+		ToolName:   toolName,
+	})
+}
+
+// findBuildProps finds the Directory.Build.props file that applies to the path, followed by any parent files that it
+// imports, in order from nearest to furthest.
+func (m *dotnetManager) findBuildProps() ([]*msbuildProject, error) {
+	var found []*msbuildProject
+	dir := m.path
+	for {
+		props, propsDir, err := findMSBuildFileAbove(m.fsys, dir, "Directory.Build.props")
+		if err != nil || props == nil {
+			return found, err
+		}
+		found = append(found, props)
+		if !props.importsParent("Directory.Build.props") || propsDir == "." {
+			return found, nil
+		}
+		dir = filepath.Dir(propsDir)
+	}
+}
+
+// findProps finds the nearest MSBuild file with the given name in the path or its parent directories.
+func (m *dotnetManager) findProps(filename string) (*msbuildProject, error) {
+	props, _, err := findMSBuildFileAbove(m.fsys, m.path, filename)
+	return props, err
+}
+
+func findMSBuildFileAbove(fsys fs.FS, dir, filename string) (*msbuildProject, string, error) {
+	for {
+		var project msbuildProject
+		err := parseXML(fsys, dir, filename, &project)
+		if err == nil {
+			return &project, dir, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return nil, "", nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// importsParent checks if the file imports the same-named file from a parent directory, e.g. using:
+// <Import Project="$([MSBuild]::GetPathOfFileAbove('Directory.Build.props', '$(MSBuildThisFileDirectory)../'))" />
+func (p *msbuildProject) importsParent(filename string) bool {
+	for _, imp := range p.Imports {
+		if strings.Contains(imp.Project, "GetPathOfFileAbove") && strings.Contains(imp.Project, filename) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *msbuildProject) addProperties(properties map[string]string) {
+	for _, pg := range p.PropertyGroups {
+		for _, prop := range pg.Properties {
+			properties[prop.XMLName.Local] = expandMSBuildProperties(strings.TrimSpace(prop.Value), properties)
+		}
+	}
+}
+
+var msbuildPropertyPatt = regexp.MustCompile(`\$\((\w+)\)`)
+
+// expandMSBuildProperties replaces simple property references such as "$(SerilogVersion)".
+func expandMSBuildProperties(s string, properties map[string]string) string {
+	if !strings.Contains(s, "$(") {
+		return s
+	}
+	return msbuildPropertyPatt.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := properties[ref[2:len(ref)-1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// parseXML decodes an XML file. A syntax error is returned as a *Diagnostic.
+func parseXML(fsys fs.FS, path, filename string, dest any) error {
+	f, err := fsys.Open(filepath.Join(path, filename))
 	if err != nil {
 		return err
 	}
 	defer f.Close()
-
 	if err := xml.NewDecoder(f).Decode(dest); err != nil {
-		return err
+		return newDiagnostic(filepath.Join(path, filename), nil, fmt.Errorf("failed to parse as XML: %w", err))
 	}
 	return nil
 }
 
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *dotnetManager) Get(name string) (Dependency, bool) {
-	// First check direct dependencies from .csproj files
-	for _, csproj := range m.csprojFiles {
-		for _, itemGroup := range csproj.ItemGroups {
-			for _, pkgRef := range itemGroup.PackageReferences {
-				if pkgRef.Include == name {
-					version := m.getLockedVersion(pkgRef.Include)
-					if version == "" && m.isSpecificVersion(pkgRef.Version) {
-						version = pkgRef.Version
-					} else if version == "" {
-						version = ""
-					}
-					return Dependency{
-						Name:       pkgRef.Include,
-						Constraint: pkgRef.Version,
-						Version:    version,
-						IsDirect:   true,
-						ToolName:   "dotnet",
-					}, true
-				}
-			}
+	// First check direct dependencies from project files
+	for _, d := range m.direct {
+		if d.Name == name {
+			return d, true
 		}
 	}
 
@@ -152,29 +371,19 @@ func (m *dotnetManager) isSpecificVersion(version string) bool {
 		return false
 	}
 	// Check if it's a simple version number like "1.0.0" or "9.0.6"
-	// Exclude ranges like ">=1.0.0", "^1.0.0", "1.0.0-*", etc.
-	return !strings.ContainsAny(version, "><=^~*")
+	// Exclude ranges like ">=1.0.0", "^1.0.0", "1.0.0-*", and unresolved properties like "$(Version)".
+	return !strings.ContainsAny(version, "><=^~*$")
 }
 
 func (m *dotnetManager) Find(pattern string) []Dependency {
 	var deps []Dependency
 	seen := make(map[string]bool)
 
-	// First, add direct dependencies from .csproj files
-	for _, csproj := range m.csprojFiles {
-		for _, itemGroup := range csproj.ItemGroups {
-			for _, pkgRef := range itemGroup.PackageReferences {
-				if wildcard.Match(pattern, pkgRef.Include) && !seen[pkgRef.Include] {
-					seen[pkgRef.Include] = true
-					deps = append(deps, Dependency{
-						Name:       pkgRef.Include,
-						Constraint: pkgRef.Version,
-						Version:    m.getLockedVersion(pkgRef.Include),
-						IsDirect:   true,
-						ToolName:   "dotnet",
-					})
-				}
-			}
+	// First, add direct dependencies from project files
+	for _, d := range m.direct {
+		if wildcard.Match(pattern, d.Name) {
+			seen[d.Name] = true
+			deps = append(deps, d)
 		}
 	}
 
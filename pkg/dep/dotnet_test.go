@@ -298,3 +298,99 @@ func TestDotnetManagerVersionRange(t *testing.T) {
 	assert.Equal(t, "[8.0.0,9.0.0)", dep.Constraint)
 	assert.Equal(t, "", dep.Version) // Should be empty for version ranges
 }
+
+func TestDotnetManagerCentralPackageManagement(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Directory.Build.props": &fstest.MapFile{Data: []byte(`<Project>
+  <PropertyGroup>
+    <SerilogVersion>3.1.1</SerilogVersion>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="StyleCop.Analyzers" Version="1.1.118" />
+  </ItemGroup>
+</Project>`)},
+		"src/Directory.Build.props": &fstest.MapFile{Data: []byte(`<Project>
+  <Import Project="$([MSBuild]::GetPathOfFileAbove('Directory.Build.props', '$(MSBuildThisFileDirectory)../'))" />
+  <ItemGroup>
+    <PackageReference Include="Serilog" Version="$(SerilogVersion)" />
+  </ItemGroup>
+</Project>`)},
+		"Directory.Packages.props": &fstest.MapFile{Data: []byte(`<Project>
+  <PropertyGroup>
+    <ManagePackageVersionsCentrally>true</ManagePackageVersionsCentrally>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageVersion Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageVersion Include="Microsoft.EntityFrameworkCore" Version="[8.0.0,9.0.0)" />
+    <GlobalPackageReference Include="Nerdbank.GitVersioning" Version="3.6.133" />
+  </ItemGroup>
+</Project>`)},
+		"src/App/App.fsproj": &fstest.MapFile{Data: []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" />
+    <PackageReference Include="Microsoft.EntityFrameworkCore" VersionOverride="8.0.4" />
+  </ItemGroup>
+</Project>`)},
+	}
+
+	manager := newDotnetManager(fsys, "src/App")
+	require.NoError(t, manager.Init())
+
+	cases := []struct {
+		name                string
+		constraint, version string
+		devOnly             bool
+	}{
+		{"Newtonsoft.Json", "13.0.3", "13.0.3", false},
+		{"Microsoft.EntityFrameworkCore", "8.0.4", "8.0.4", false},
+		{"Serilog", "3.1.1", "3.1.1", false},
+		{"StyleCop.Analyzers", "1.1.118", "1.1.118", false},
+		{"Nerdbank.GitVersioning", "3.6.133", "3.6.133", true},
+	}
+	for _, c := range cases {
+		dep, found := manager.Get(c.name)
+		assert.True(t, found, c.name)
+		assert.Equal(t, Dependency{
+			Name:       c.name,
+			Constraint: c.constraint,
+			Version:    c.version,
+			IsDirect:   true,
+			IsDevOnly:  c.devOnly,
+			ToolName:   "dotnet",
+		}, dep, c.name)
+	}
+	assert.Len(t, manager.Find("*"), len(cases))
+}
+
+func TestDotnetManagerPackagesConfig(t *testing.T) {
+	fsys := fstest.MapFS{
+		"packages.config": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="Microsoft.AspNet.Mvc" version="5.2.9" targetFramework="net472" />
+  <package id="Microsoft.CodeDom.Providers.DotNetCompilerPlatform" version="2.0.1" targetFramework="net472"
+    developmentDependency="true" />
+</packages>`)},
+		"Legacy.vbproj": &fstest.MapFile{Data: []byte(`<Project ToolsVersion="15.0">
+  <PropertyGroup>
+    <TargetFrameworkVersion>v4.7.2</TargetFrameworkVersion>
+  </PropertyGroup>
+</Project>`)},
+	}
+
+	manager := newDotnetManager(fsys, ".")
+	require.NoError(t, manager.Init())
+
+	dep, found := manager.Get("Microsoft.AspNet.Mvc")
+	assert.True(t, found)
+	assert.Equal(t, Dependency{
+		Name:       "Microsoft.AspNet.Mvc",
+		Constraint: "5.2.9",
+		Version:    "5.2.9",
+		IsDirect:   true,
+		ToolName:   "nuget",
+	}, dep)
+
+	dep, found = manager.Get("Microsoft.CodeDom.Providers.DotNetCompilerPlatform")
+	assert.True(t, found)
+	assert.True(t, dep.IsDevOnly)
+}
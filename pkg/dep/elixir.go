@@ -18,8 +18,8 @@ type elixirManager struct {
 	path string
 
 	initOnce sync.Once
-	required map[string]string
-	resolved map[string]string
+	deps     map[string]Dependency
+	diagnostics
 }
 
 func newElixirManager(fsys fs.FS, path string) Manager {
@@ -38,93 +38,303 @@ func (m *elixirManager) Init() error {
 }
 
 func (m *elixirManager) parse() error {
-	mixfile, err := m.fsys.Open(filepath.Join(m.path, "mix.exs"))
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
-		}
+	mixfile, err := m.parseMixfileAt(m.path)
+	if err != nil || mixfile == nil {
 		return err
 	}
-	defer mixfile.Close()
-	m.required = parseMixfile(mixfile)
 
-	mixfileLock, err := m.fsys.Open(filepath.Join(m.path, "mix.lock"))
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
+	m.deps = make(map[string]Dependency)
+	for _, d := range mixfile.deps {
+		m.deps[d.Name] = d
+	}
+
+	// An umbrella project includes the dependencies of its child apps.
+	if mixfile.appsPath != "" {
+		children, err := fs.Glob(m.fsys, filepath.Join(m.path, mixfile.appsPath, "*", "mix.exs"))
+		if err != nil {
+			return err
+		}
+		apps := make(map[string]bool, len(children))
+		for _, child := range children {
+			apps[filepath.Base(filepath.Dir(child))] = true
 		}
+		for _, child := range children {
+			childMixfile, err := m.parseMixfileAt(filepath.Dir(child))
+			if err != nil {
+				return err
+			}
+			for _, d := range childMixfile.deps {
+				// Sibling apps (in_umbrella dependencies) are part of the project itself.
+				if _, ok := m.deps[d.Name]; !ok && !apps[d.Name] {
+					m.deps[d.Name] = d
+				}
+			}
+		}
+	}
+
+	lockPath, isShared, err := m.findLockFile(mixfile)
+	if err != nil || lockPath == "" {
+		return err
+	}
+	mixfileLock, err := m.fsys.Open(lockPath)
+	if err != nil {
 		return err
 	}
 	defer mixfileLock.Close()
-	m.resolved = parseMixfileLock(mixfileLock)
+	for name, locked := range parseMixfileLock(mixfileLock) {
+		if d, ok := m.deps[name]; ok {
+			// Update existing dependency (preserve IsDirect status)
+			d.Version = locked.Version
+			if locked.Source != "" && (d.Source == "" || strings.HasPrefix(d.Source, "git+")) {
+				d.Source = locked.Source // Includes the locked revision
+			}
+			if locked.Registry != "" && d.Source == "" {
+				d.Registry = locked.Registry
+			}
+			m.deps[name] = d
+		} else if !isShared {
+			// New dependency only in the lock file (indirect)
+			// An umbrella child does not list these: they belong to the umbrella root.
+			locked.ToolName = "mix"
+			m.deps[name] = locked
+		}
+	}
 	return nil
 }
 
-func (m *elixirManager) Get(name string) (Dependency, bool) {
-	req, ok := m.required[name]
-	if !ok {
-		return Dependency{}, false
+func (m *elixirManager) parseMixfileAt(path string) (*mixfile, error) {
+	f, err := m.fsys.Open(filepath.Join(path, "mix.exs"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	return parseMixfile(f)
+}
+
+// findLockFile finds the mix.lock file that applies to the project.
+// Umbrella children share the lock file of the umbrella root, normally configured with the "lockfile" option.
+func (m *elixirManager) findLockFile(mf *mixfile) (lockPath string, isShared bool, err error) {
+	if mf.lockfile != "" {
+		lockPath = filepath.Join(m.path, mf.lockfile)
+		if _, err := fs.Stat(m.fsys, lockPath); err == nil {
+			return lockPath, lockPath != filepath.Join(m.path, "mix.lock"), nil
+		}
+	}
+	lockPath = filepath.Join(m.path, "mix.lock")
+	if _, err := fs.Stat(m.fsys, lockPath); err == nil {
+		return lockPath, false, nil
 	}
-	return Dependency{
-		Name:       name,
-		Constraint: req,
-		Version:    m.resolved[name],
-		IsDirect:   true, // Dependencies from mix.exs are direct
-		ToolName:   "mix",
-	}, true
+	// Look for an umbrella root in the parent directories.
+	dir := m.path
+	for dir != "." && dir != "/" && dir != "" {
+		dir = filepath.Dir(dir)
+		parent, err := m.parseMixfileAt(dir)
+		if err != nil {
+			return "", false, err
+		}
+		if parent != nil && parent.appsPath != "" {
+			lockPath = filepath.Join(dir, "mix.lock")
+			if _, err := fs.Stat(m.fsys, lockPath); err == nil {
+				return lockPath, true, nil
+			}
+			return "", false, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (m *elixirManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
 }
 
 func (m *elixirManager) Find(pattern string) []Dependency {
 	var deps []Dependency
-	for name, constraint := range m.required {
+	for name, dep := range m.deps {
 		if wildcard.Match(pattern, name) {
-			deps = append(deps, Dependency{
-				Name:       name,
-				Constraint: constraint,
-				Version:    m.resolved[name],
-				IsDirect:   true, // Dependencies from mix.exs are direct
-				ToolName:   "mix",
-			})
+			deps = append(deps, dep)
 		}
 	}
 	return deps
 }
 
-var mixPatt = regexp.MustCompile(`^\s*\{\s*:(\w+),\s*"([^"]+)"`)
+var (
+	mixDepsFuncPatt = regexp.MustCompile(`defp?\s+deps(?:\(\))?\s+do\b`)
+	mixDepPatt      = regexp.MustCompile(`(?s)^\{\s*:(\w+)\s*,?(.*)\}$`)
+	mixAppsPathPatt = regexp.MustCompile(`\bapps_path:\s*"([^"]+)"`)
+	mixLockfilePatt = regexp.MustCompile(`\blockfile:\s*"([^"]+)"`)
+)
 
-func parseMixfile(r io.Reader) map[string]string {
-	deps := make(map[string]string)
-	scanner := bufio.NewScanner(r)
+type mixfile struct {
+	deps     []Dependency
+	appsPath string // Set for umbrella projects
+	lockfile string // A custom lock file path, used by umbrella children
+}
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "{:") {
-			matches := mixPatt.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				name := matches[1]
-				version := ""
-				if len(matches) >= 2 {
-					version = matches[2]
-				}
-				deps[name] = version
+// parseMixfile parses dependencies and relevant project options from a mix.exs file.
+func parseMixfile(r io.Reader) (*mixfile, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	content := string(b)
+
+	var mf mixfile
+	if matches := mixAppsPathPatt.FindStringSubmatch(content); matches != nil {
+		mf.appsPath = matches[1]
+	}
+	if matches := mixLockfilePatt.FindStringSubmatch(content); matches != nil {
+		mf.lockfile = matches[1]
+	}
+
+	// Only read the list returned by the deps function, if there is one.
+	if loc := mixDepsFuncPatt.FindStringIndex(content); loc != nil {
+		if start := strings.Index(content[loc[1]:], "["); start != -1 {
+			start += loc[1]
+			if end := matchingBracket(content, start); end != -1 {
+				content = content[start+1 : end]
 			}
 		}
 	}
 
-	return deps
+	for i := 0; i < len(content); i++ {
+		if !strings.HasPrefix(content[i:], "{:") {
+			continue
+		}
+		end := matchingBracket(content, i)
+		if end == -1 {
+			break
+		}
+		if d, ok := parseMixDep(content[i : end+1]); ok {
+			mf.deps = append(mf.deps, d)
+		}
+		i = end
+	}
+
+	return &mf, nil
 }
 
-var mixLockPatt = regexp.MustCompile(`^\s{2}"([a-zA-Z0-9_-]+)": {:(\w+), :(\w+), "([^"]+)", .*}$`)
+// parseMixDep parses a dependency tuple, e.g. `{:phoenix_live_reload, "~> 1.2", only: :dev}`.
+func parseMixDep(tuple string) (Dependency, bool) {
+	matches := mixDepPatt.FindStringSubmatch(tuple)
+	if matches == nil {
+		return Dependency{}, false
+	}
+	d := Dependency{
+		Name:     matches[1],
+		IsDirect: true, // Dependencies from mix.exs are direct
+		ToolName: "mix",
+	}
+	for _, arg := range splitRubyArgs(matches[2]) {
+		if strings.HasPrefix(arg, `"`) {
+			d.Constraint = strings.Trim(arg, `"`)
+			continue
+		}
+		option := gemOptionPatt.FindStringSubmatch(arg)
+		if option == nil {
+			continue
+		}
+		value := strings.TrimSpace(option[2])
+		switch option[1] {
+		case "only":
+			d.IsDevOnly = isDevOnlyMixEnvs(gemSymbolsPatt.FindAllString(value, -1))
+		case "git":
+			d.Source = "git+" + unquoteRuby(value)
+		case "github":
+			d.Source = "git+https://github.com/" + unquoteRuby(value) + ".git"
+		case "path":
+			d.Source = "path+" + unquoteRuby(value)
+		case "in_umbrella":
+			if value == "true" {
+				d.Source = "path+../" + d.Name
+			}
+		}
+	}
+	return d, true
+}
+
+// isDevOnlyMixEnvs checks if a list of Mix environments contains only dev or test.
+func isDevOnlyMixEnvs(envs []string) bool {
+	if len(envs) == 0 {
+		return false
+	}
+	for _, env := range envs {
+		if env != "dev" && env != "test" {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingBracket returns the index of the bracket closing the one at position start, or -1 if not found.
+// Brackets inside double-quoted strings are ignored.
+func matchingBracket(s string, start int) int {
+	var depth int
+	var inString bool
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var (
+	mixLockPatt = regexp.MustCompile(`^\s*"([\w-]+)":\s*\{:(hex|git),\s*([^,]+),\s*"([^"]+)"`)
+	// The Hex repository of a package, before the outer checksum at the end of its entry, e.g. `"hexpm", "cf78…"},`.
+	mixLockRepoPatt = regexp.MustCompile(`,\s*"([^"]+)",\s*"[0-9a-f]+"\},?\s*$`)
+)
 
-func parseMixfileLock(r io.Reader) map[string]string {
-	deps := make(map[string]string)
+// parseMixfileLock parses resolved versions from Hex packages and revisions from Git packages in mix.lock.
+// Packages from the public Hex repository (or an organization in it) get its URL as their registry.
+func parseMixfileLock(r io.Reader) map[string]Dependency {
+	deps := make(map[string]Dependency)
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
 	for scanner.Scan() {
-		line := scanner.Text()
-		matches := mixLockPatt.FindStringSubmatch(line)
-		if len(matches) >= 4 {
-			deps[matches[1]] = matches[4]
+		matches := mixLockPatt.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		name := matches[1]
+		if matches[2] == "git" {
+			deps[name] = Dependency{
+				Name:   name,
+				Source: "git+" + strings.Trim(matches[3], `"`) + "#" + matches[4],
+			}
+			continue
+		}
+		d := Dependency{
+			Name:    name,
+			Version: matches[4],
+		}
+		// Older lock files do not record the repository, which was always "hexpm".
+		repo := "hexpm"
+		if m := mixLockRepoPatt.FindStringSubmatch(scanner.Text()); m != nil {
+			repo = m[1]
+		}
+		if repo == "hexpm" || strings.HasPrefix(repo, "hexpm:") {
+			d.Registry = "https://hex.pm/"
 		}
+		deps[name] = d
 	}
 
 	return deps
@@ -77,6 +77,7 @@ end
 			Constraint: "~> 1.6.5",
 			IsDirect:   true,
 			ToolName:   "mix",
+			Registry:   "https://hex.pm/",
 		}}},
 	}
 	for _, c := range toFind {
@@ -95,6 +96,7 @@ end
 			Constraint: "~> 1.6.5",
 			IsDirect:   true,
 			ToolName:   "mix",
+			Registry:   "https://hex.pm/",
 		}, found: true},
 	}
 	for _, c := range toGet {
@@ -103,3 +105,141 @@ end
 		assert.Equal(t, c.dependency, d)
 	}
 }
+
+func TestElixirUmbrella(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mix.exs": {Data: []byte(`
+defmodule Shop.Umbrella.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      apps_path: "apps",
+      version: "0.1.0",
+      start_permanent: Mix.env() == :prod,
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:credo, "~> 1.7", only: [:dev, :test], runtime: false}
+    ]
+  end
+end
+`)},
+		"mix.lock": { //nolint:lll
+			Data: []byte(`%{
+  "credo": {:hex, :credo, "1.7.1", "6e26bbcc9e22eefbff7e43188e69924e78818e2fe6282487d0703652bc20fd62", [:mix], [], "hexpm", "e9871c6095a4c0381c89b6aa98bc6260a8ba6addccf7f6a53da8849c748a58a2"},
+  "ecto": {:hex, :ecto, "3.11.1", "4b4972b717e7ca83d30121b12998f5fcdc62ba0ed4f20fd390f16f3270d85c3e", [:mix], [], "hexpm", "ebd3d3772cd0dfcd8d772659e41ed527c28b2a8bde4b00fe03e0463da0f1983b"},
+  "phoenix": {:hex, :phoenix, "1.7.10", "02189140a61b2ce85bb633a9b6fd02dff705a5f1596869547aeb2b2b95edd729", [:mix], [], "hexpm", "cf784932e010fd736d656d7fead6a584a4498efefe5b8227e9f383bf15bb79d0"},
+  "telemetry": {:hex, :telemetry, "1.2.1", "68fdfe8d8f05a8428483a97d7aab2f268aaff24b49e0f599faa091f1d4e7f61c", [:rebar3], [], "hexpm", "dad9ce9d8effc621708f99eac538ef1cbe05d6a874dd741de2e689c47feafed5"},
+  "billing": {:hex, :billing, "2.0.1", "5b1f6a0c2d4e8f9a7b3c1d0e2f4a6b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a", [:mix], [], "acme_repo", "0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9"},
+  "toolkit": {:git, "https://github.com/example/toolkit.git", "0f4ad2dbb2e3e7f4c9bf8a5d5e1e0b3f2f8f8c11", [branch: "main"]},
+}
+`),
+		},
+		"apps/shop/mix.exs": {Data: []byte(`
+defmodule Shop.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :shop,
+      build_path: "../../_build",
+      config_path: "../../config/config.exs",
+      deps_path: "../../deps",
+      lockfile: "../../mix.lock",
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:ecto, "~> 3.11"},
+      {:toolkit, git: "https://github.com/example/toolkit.git", branch: "main"}
+    ]
+  end
+end
+`)},
+		"apps/shop_web/mix.exs": {Data: []byte(`
+defmodule ShopWeb.MixProject do
+  use Mix.Project
+
+  def project do
+    [
+      app: :shop_web,
+      deps: deps()
+    ]
+  end
+
+  defp deps do
+    [
+      {:phoenix,
+       "~> 1.7.10"},
+      {:shop, in_umbrella: true},
+      {:local_lib, path: "../../vendor/local_lib"},
+      {:floki, ">= 0.30.0", only: :test}
+    ]
+  end
+end
+`)},
+	}
+
+	root, err := dep.GetManager(dep.ManagerTypeElixir, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, root.Init())
+
+	rootCases := []struct {
+		name       string
+		dependency dep.Dependency
+		found      bool
+	}{
+		{name: "credo", dependency: dep.Dependency{
+			Name: "credo", Constraint: "~> 1.7", Version: "1.7.1", IsDirect: true, IsDevOnly: true, ToolName: "mix",
+			Registry: "https://hex.pm/",
+		}, found: true},
+		{name: "phoenix", dependency: dep.Dependency{
+			Name: "phoenix", Constraint: "~> 1.7.10", Version: "1.7.10", IsDirect: true, ToolName: "mix",
+			Registry: "https://hex.pm/",
+		}, found: true},
+		{name: "telemetry", dependency: dep.Dependency{
+			Name: "telemetry", Version: "1.2.1", ToolName: "mix", Registry: "https://hex.pm/",
+		}, found: true},
+		{name: "billing", dependency: dep.Dependency{
+			Name: "billing", Version: "2.0.1", ToolName: "mix", // From a self-hosted repository, with an unknown URL.
+		}, found: true},
+		{name: "shop"},
+	}
+	for _, c := range rootCases {
+		d, ok := root.Get(c.name)
+		assert.Equal(t, c.found, ok, c.name)
+		assert.Equal(t, c.dependency, d, c.name)
+	}
+
+	shop, err := dep.GetManager(dep.ManagerTypeElixir, fsys, "apps/shop")
+	require.NoError(t, err)
+	require.NoError(t, shop.Init())
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "ecto", Constraint: "~> 3.11", Version: "3.11.1", IsDirect: true, ToolName: "mix",
+			Registry: "https://hex.pm/"},
+		{
+			Name:     "toolkit",
+			IsDirect: true,
+			ToolName: "mix",
+			Source:   "git+https://github.com/example/toolkit.git#0f4ad2dbb2e3e7f4c9bf8a5d5e1e0b3f2f8f8c11",
+		},
+	}, shop.Find("*"))
+
+	// Without the lockfile option, the umbrella root's lock file is found in parent directories.
+	shopWeb, err := dep.GetManager(dep.ManagerTypeElixir, fsys, "apps/shop_web")
+	require.NoError(t, err)
+	require.NoError(t, shopWeb.Init())
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "phoenix", Constraint: "~> 1.7.10", Version: "1.7.10", IsDirect: true, ToolName: "mix",
+			Registry: "https://hex.pm/"},
+		{Name: "shop", IsDirect: true, ToolName: "mix", Source: "path+../shop"},
+		{Name: "local_lib", IsDirect: true, ToolName: "mix", Source: "path+../../vendor/local_lib"},
+		{Name: "floki", Constraint: ">= 0.30.0", IsDirect: true, IsDevOnly: true, ToolName: "mix"},
+	}, shopWeb.Find("*"))
+}
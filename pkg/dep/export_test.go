@@ -0,0 +1,4 @@
+package dep
+
+// Unregister exposes unregister to external tests.
+var Unregister = unregister
@@ -0,0 +1,131 @@
+package dep
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+// githubActionsManager reads the actions and reusable workflows used in GitHub Actions workflows.
+type githubActionsManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	diagnostics
+}
+
+func newGitHubActionsManager(fsys fs.FS, path string) Manager {
+	return &githubActionsManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *githubActionsManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+type githubWorkflowSteps struct {
+	Steps []struct {
+		Uses string `yaml:"uses"`
+	} `yaml:"steps"`
+}
+
+// githubWorkflow is a workflow file. Jobs use actions in steps, or call reusable workflows.
+// See: https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions
+type githubWorkflow struct {
+	Jobs map[string]struct {
+		githubWorkflowSteps `yaml:",inline"`
+		Uses                string `yaml:"uses"`
+	} `yaml:"jobs"`
+}
+
+// githubAction is the metadata file of a composite action, which can use other actions.
+type githubAction struct {
+	Runs githubWorkflowSteps `yaml:"runs"`
+}
+
+func (m *githubActionsManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
+	for _, pattern := range []string{".github/workflows/*.yml", ".github/workflows/*.yaml"} {
+		matches, err := fs.Glob(m.fsys, filepath.Join(m.path, pattern))
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			var workflow githubWorkflow
+			if err := parseYAML(m.fsys, filepath.Dir(match), filepath.Base(match), &workflow); m.report(err) != nil {
+				return err
+			}
+			for _, job := range workflow.Jobs {
+				m.addUses(job.Uses)
+				for _, step := range job.Steps {
+					m.addUses(step.Uses)
+				}
+			}
+		}
+	}
+
+	for _, pattern := range []string{".github/actions/*/action.yml", ".github/actions/*/action.yaml"} {
+		matches, err := fs.Glob(m.fsys, filepath.Join(m.path, pattern))
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			var action githubAction
+			if err := parseYAML(m.fsys, filepath.Dir(match), filepath.Base(match), &action); m.report(err) != nil {
+				return err
+			}
+			for _, step := range action.Runs.Steps {
+				m.addUses(step.Uses)
+			}
+		}
+	}
+
+	return nil
+}
+
+// addUses adds the dependency from a "uses" value, e.g. "actions/checkout@v4". Local actions are skipped, as
+// are Docker images, which belong to the "docker" manager.
+func (m *githubActionsManager) addUses(uses string) {
+	if uses == "" || strings.HasPrefix(uses, "./") || strings.HasPrefix(uses, "docker://") {
+		return
+	}
+	name, ref, _ := strings.Cut(uses, "@")
+	if _, exists := m.deps[name]; exists {
+		return
+	}
+	vendor, _, _ := strings.Cut(name, "/")
+	m.deps[name] = Dependency{
+		Vendor:   vendor,
+		Name:     name,
+		Version:  ref, // A tag, branch or commit SHA
+		IsDirect: true,
+		ToolName: "github-actions",
+	}
+}
+
+func (m *githubActionsManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *githubActionsManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
@@ -16,6 +16,7 @@ type goManager struct {
 
 	initOnce sync.Once
 	file     *modfile.File
+	diagnostics
 }
 
 func newGoManager(fsys fs.FS, path string) Manager {
@@ -38,9 +39,11 @@ func (m *goManager) init() error {
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
-	f, err := modfile.Parse("go.mod", b, nil)
+	filename := filepath.Join(m.path, "go.mod")
+	f, err := modfile.Parse(filename, b, nil)
 	if err != nil {
-		return err
+		m.file = &modfile.File{}
+		return m.report(newDiagnostic(filename, b, err))
 	}
 	m.file = f
 	return nil
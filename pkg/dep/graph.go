@@ -0,0 +1,126 @@
+package dep
+
+import (
+	"slices"
+	"strings"
+)
+
+// GraphManager is implemented by managers that can read the dependency graph, usually from a lock file.
+type GraphManager interface {
+	Manager
+
+	// Graph returns the dependency graph, or nil if it is not available (e.g. if there is no lock file).
+	// Manager.Init must be called first.
+	Graph() *Graph
+}
+
+// Graph is a dependency graph. Its nodes are the dependencies of a manager, identified by name, and its edges link
+// each dependency to the packages it requires.
+type Graph struct {
+	nodes map[string]Dependency
+	edges map[string][]string
+}
+
+// newGraph builds a graph from dependencies, and the names of the packages required by each one.
+// Edges to unknown packages, such as platform requirements, are ignored.
+func newGraph(deps map[string]Dependency, edges map[string][]string) *Graph {
+	g := &Graph{nodes: deps, edges: make(map[string][]string, len(edges))}
+	for from, to := range edges {
+		if _, ok := deps[from]; !ok {
+			continue
+		}
+		var children []string
+		for _, name := range to {
+			if _, ok := deps[name]; ok && name != from && !slices.Contains(children, name) {
+				children = append(children, name)
+			}
+		}
+		slices.Sort(children)
+		g.edges[from] = children
+	}
+	return g
+}
+
+// Nodes returns all the dependencies in the graph, sorted by name.
+func (g *Graph) Nodes() []Dependency {
+	nodes := make([]Dependency, 0, len(g.nodes))
+	for _, d := range g.nodes {
+		nodes = append(nodes, d)
+	}
+	sortDependencies(nodes)
+	return nodes
+}
+
+// Node returns a dependency by name.
+func (g *Graph) Node(name string) (Dependency, bool) {
+	d, ok := g.nodes[name]
+	return d, ok
+}
+
+// Roots returns the direct dependencies, sorted by name.
+func (g *Graph) Roots() []Dependency {
+	var roots []Dependency
+	for _, d := range g.nodes {
+		if d.IsDirect {
+			roots = append(roots, d)
+		}
+	}
+	sortDependencies(roots)
+	return roots
+}
+
+// Dependencies returns the packages required by a dependency, sorted by name.
+func (g *Graph) Dependencies(name string) []Dependency {
+	children := make([]Dependency, 0, len(g.edges[name]))
+	for _, child := range g.edges[name] {
+		children = append(children, g.nodes[child])
+	}
+	return children
+}
+
+// Why explains why a package is installed. It returns the shortest path from each direct dependency that requires
+// the package, ending with the package itself. If the package is a direct dependency, one of the paths contains
+// only the package. Paths are sorted by their first element.
+func (g *Graph) Why(name string) [][]Dependency {
+	if _, ok := g.nodes[name]; !ok {
+		return nil
+	}
+	var paths [][]Dependency
+	for _, root := range g.Roots() {
+		if path := g.shortestPath(root.Name, name); path != nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// shortestPath finds the shortest path between two packages with a breadth-first search.
+func (g *Graph) shortestPath(from, to string) []Dependency {
+	parents := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			var path []Dependency
+			for n := to; n != ""; n = parents[n] {
+				path = append(path, g.nodes[n])
+			}
+			slices.Reverse(path)
+			return path
+		}
+		for _, child := range g.edges[current] {
+			if _, seen := parents[child]; !seen {
+				parents[child] = current
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil
+}
+
+func sortDependencies(deps []Dependency) {
+	slices.SortFunc(deps, func(a, b Dependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,223 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestGraph(t *testing.T) {
+	fsys := fstest.MapFS{
+		"composer.json": {Data: []byte(`{
+			"require": {"php": "^8.2", "symfony/console": "^7.0", "monolog/monolog": "^3.0"},
+			"require-dev": {"phpunit/phpunit": "^11.0"}
+		}`)},
+		"composer.lock": {Data: []byte(`{
+			"packages": [
+				{"name": "monolog/monolog", "version": "3.8.1", "require": {"php": ">=8.1", "psr/log": "^2.0 || ^3.0"}},
+				{"name": "psr/log", "version": "3.0.2", "require": {"php": ">=8.0.0"}},
+				{"name": "symfony/console", "version": "v7.2.1", "require": {
+					"php": ">=8.2", "symfony/string": "^6.4|^7.0", "symfony/service-contracts": "^2.5|^3"
+				}},
+				{"name": "symfony/service-contracts", "version": "v3.5.1", "require": {"psr/container": "^1.1|^2.0"}},
+				{"name": "psr/container", "version": "2.0.2"},
+				{"name": "symfony/string", "version": "v7.2.0", "require": {"ext-mbstring": "*"}}
+			],
+			"packages-dev": [
+				{"name": "phpunit/phpunit", "version": "11.5.3", "require": {"psr/log": "^3.0"}}
+			]
+		}`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypePHP, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	g := m.(dep.GraphManager).Graph()
+	require.NotNil(t, g)
+
+	assert.Len(t, g.Nodes(), 7)
+	assert.Equal(t, []string{"monolog/monolog", "phpunit/phpunit", "symfony/console"}, dependencyNames(g.Roots()))
+	assert.Equal(t, []string{"symfony/service-contracts", "symfony/string"},
+		dependencyNames(g.Dependencies("symfony/console")))
+	assert.Empty(t, g.Dependencies("symfony/string"), "platform requirements are ignored")
+
+	paths := g.Why("psr/log")
+	require.Len(t, paths, 2)
+	assert.Equal(t, []string{"monolog/monolog", "psr/log"}, dependencyNames(paths[0]))
+	assert.Equal(t, []string{"phpunit/phpunit", "psr/log"}, dependencyNames(paths[1]))
+	assert.Equal(t, "3.0.2", paths[0][1].Version)
+
+	paths = g.Why("psr/container")
+	require.Len(t, paths, 1)
+	assert.Equal(t, []string{"symfony/console", "symfony/service-contracts", "psr/container"}, dependencyNames(paths[0]))
+
+	paths = g.Why("symfony/console")
+	require.Len(t, paths, 1)
+	assert.Equal(t, []string{"symfony/console"}, dependencyNames(paths[0]))
+
+	assert.Nil(t, g.Why("foo/bar"))
+}
+
+func TestGraph_NoLockFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"package.json": {Data: []byte(`{"dependencies": {"express": "^4.21.0"}}`)},
+	}
+	m, err := dep.GetManager(dep.ManagerTypeJavaScript, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+	assert.Nil(t, m.(dep.GraphManager).Graph())
+}
+
+func TestGraph_LockFiles(t *testing.T) {
+	cases := []struct {
+		name        string
+		managerType string
+		fsys        fstest.MapFS
+		why         string
+		path        []string
+	}{
+		{
+			name:        "npm",
+			managerType: dep.ManagerTypeJavaScript,
+			fsys: fstest.MapFS{
+				"package.json":      {Data: testNPMPackageJSON},
+				"package-lock.json": {Data: testNPMPackageLock},
+			},
+			why:  "js-tokens",
+			path: []string{"gatsby", "@babel/code-frame", "js-tokens"},
+		},
+		{
+			name:        "npm v1",
+			managerType: dep.ManagerTypeJavaScript,
+			fsys: fstest.MapFS{
+				"package.json": {Data: []byte(`{"dependencies": {"debug": "^2.6.9"}}`)},
+				"package-lock.json": {Data: []byte(`{
+					"lockfileVersion": 1,
+					"dependencies": {
+						"debug": {"version": "2.6.9", "requires": {"ms": "2.0.0"}},
+						"ms": {"version": "2.0.0"}
+					}
+				}`)},
+			},
+			why:  "ms",
+			path: []string{"debug", "ms"},
+		},
+		{
+			name:        "pnpm",
+			managerType: dep.ManagerTypeJavaScript,
+			fsys: fstest.MapFS{
+				"package.json":   {Data: testPNPMPackageJSON},
+				"pnpm-lock.yaml": {Data: testPNPMLock},
+			},
+			why:  "ms",
+			path: []string{"@strapi/strapi", "@strapi/admin", "jsonwebtoken", "ms"},
+		},
+		{
+			name:        "cargo",
+			managerType: dep.ManagerTypeRust,
+			fsys: fstest.MapFS{
+				"Cargo.toml": {Data: testCargoTOML},
+				"Cargo.lock": {Data: testCargoLock},
+			},
+			why:  "libc",
+			path: []string{"rand", "libc"},
+		},
+		{
+			name:        "poetry",
+			managerType: dep.ManagerTypePython,
+			fsys: fstest.MapFS{
+				"pyproject.toml": {Data: []byte(`
+[tool.poetry.dependencies]
+python = "^3.12"
+requests = "^2.32"
+`)},
+				"poetry.lock": {Data: []byte(`
+[[package]]
+name = "requests"
+version = "2.32.3"
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+Charset_Normalizer = ">=2,<4"
+
+[[package]]
+name = "certifi"
+version = "2025.1.31"
+
+[[package]]
+name = "charset-normalizer"
+version = "3.4.1"
+`)},
+			},
+			why:  "charset-normalizer",
+			path: []string{"requests", "charset-normalizer"},
+		},
+		{
+			name:        "uv",
+			managerType: dep.ManagerTypePython,
+			fsys: fstest.MapFS{
+				"pyproject.toml": {Data: []byte(`
+[project]
+name = "example"
+dependencies = ["flask>=3.0"]
+`)},
+				"uv.lock": {Data: []byte(`
+version = 1
+
+[[package]]
+name = "example"
+version = "0.1.0"
+source = { virtual = "." }
+dependencies = [{ name = "flask" }]
+
+[[package]]
+name = "flask"
+version = "3.1.0"
+dependencies = [{ name = "jinja2" }, { name = "werkzeug" }]
+
+[[package]]
+name = "jinja2"
+version = "3.1.5"
+dependencies = [{ name = "markupsafe" }]
+
+[[package]]
+name = "markupsafe"
+version = "3.0.2"
+
+[[package]]
+name = "werkzeug"
+version = "3.1.3"
+dependencies = [{ name = "markupsafe" }]
+`)},
+			},
+			why:  "markupsafe",
+			path: []string{"flask", "jinja2", "markupsafe"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, err := dep.GetManager(c.managerType, c.fsys, ".")
+			require.NoError(t, err)
+			require.NoError(t, m.Init())
+
+			g := m.(dep.GraphManager).Graph()
+			require.NotNil(t, g)
+			paths := g.Why(c.why)
+			require.NotEmpty(t, paths)
+			assert.Equal(t, c.path, dependencyNames(paths[0]))
+		})
+	}
+}
+
+func dependencyNames(deps []dep.Dependency) []string {
+	var n []string
+	for _, d := range deps {
+		n = append(n, d.Name)
+	}
+	return n
+}
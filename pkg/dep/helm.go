@@ -0,0 +1,126 @@
+package dep
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+// helmManager reads the dependencies of a Helm chart.
+type helmManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	platform []Dependency
+	diagnostics
+}
+
+func newHelmManager(fsys fs.FS, path string) Manager {
+	return &helmManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *helmManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+type helmChartDependency struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	Repository string `yaml:"repository"`
+}
+
+// helmChart is the Chart.yaml file, or the requirements.yaml file in charts using API version v1.
+// See: https://helm.sh/docs/topics/charts/#the-chartyaml-file
+type helmChart struct {
+	KubeVersion  string                `yaml:"kubeVersion"`
+	Dependencies []helmChartDependency `yaml:"dependencies"`
+}
+
+func (m *helmManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
+	var chart helmChart
+	if err := parseYAML(m.fsys, m.path, "Chart.yaml", &chart); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+	if chart.KubeVersion != "" {
+		m.platform = append(m.platform, Dependency{Name: "kubernetes", Constraint: chart.KubeVersion, ToolName: "helm"})
+	}
+
+	lockFilename := "Chart.lock"
+	if len(chart.Dependencies) == 0 {
+		var requirements helmChart
+		err := parseYAML(m.fsys, m.path, "requirements.yaml", &requirements)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) && m.report(err) != nil {
+			return err
+		}
+		if err == nil {
+			chart.Dependencies = requirements.Dependencies
+			lockFilename = "requirements.lock"
+		}
+	}
+
+	for _, d := range chart.Dependencies {
+		var source string
+		if p, ok := strings.CutPrefix(d.Repository, "file://"); ok {
+			source = "path+" + p
+		}
+		m.deps[d.Name] = Dependency{
+			Name:       d.Name,
+			Constraint: d.Version,
+			IsDirect:   true,
+			ToolName:   "helm",
+			Source:     source,
+		}
+	}
+
+	var lock helmChart
+	if err := parseYAML(m.fsys, m.path, lockFilename, &lock); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return m.report(err)
+	}
+	for _, locked := range lock.Dependencies {
+		if d, ok := m.deps[locked.Name]; ok {
+			d.Version = locked.Version
+			m.deps[locked.Name] = d
+		}
+	}
+	return nil
+}
+
+func (m *helmManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *helmManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Platform returns the "kubeVersion" constraint of the chart, if any.
+func (m *helmManager) Platform() []Dependency {
+	return m.platform
+}
@@ -2,14 +2,15 @@ package dep
 
 import (
 	"bufio"
-	"encoding/xml"
 	"errors"
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
 
+	"github.com/BurntSushi/toml"
 	"github.com/IGLOU-EU/go-wildcard/v2"
 )
 
@@ -19,6 +20,7 @@ type javaManager struct {
 
 	initOnce sync.Once
 	deps     []Dependency
+	diagnostics
 }
 
 func newJavaManager(fsys fs.FS, path string) Manager {
@@ -38,20 +40,29 @@ func (m *javaManager) Init() error {
 
 func (m *javaManager) parse() error {
 	deps, err := parsePomXML(m.fsys, m.path)
-	if err != nil {
+	if m.report(err) != nil {
 		return err
 	}
 	m.deps = append(m.deps, deps...)
-	deps, err = parseBuildGradleGroovy(m.fsys, m.path)
+	catalog, err := findGradleVersionCatalog(m.fsys, m.path)
+	if m.report(err) != nil {
+		return err
+	}
+	deps, err = parseBuildGradle(m.fsys, m.path, "build.gradle", catalog)
 	if err != nil {
 		return err
 	}
 	m.deps = append(m.deps, deps...)
-	deps, err = parseBuildGradleKotlin(m.fsys, m.path)
+	deps, err = parseBuildGradle(m.fsys, m.path, "build.gradle.kts", catalog)
 	if err != nil {
 		return err
 	}
 	m.deps = append(m.deps, deps...)
+	locked, err := parseGradleLockfile(m.fsys, m.path)
+	if err != nil {
+		return err
+	}
+	m.mergeGradleLocked(locked)
 	deps, err = parseBuildSBT(m.fsys, m.path)
 	if err != nil {
 		return err
@@ -60,6 +71,28 @@ func (m *javaManager) parse() error {
 	return nil
 }
 
+// mergeGradleLocked adds resolved versions from gradle.lockfile.
+// Declared versions are kept as the constraint, and packages only found in the lockfile are added as indirect.
+func (m *javaManager) mergeGradleLocked(locked []Dependency) {
+	for _, l := range locked {
+		var found bool
+		for i, d := range m.deps {
+			if d.ToolName != "gradle" || d.Name != l.Name {
+				continue
+			}
+			found = true
+			if d.Version != "" && d.Version != l.Version {
+				d.Constraint = d.Version
+			}
+			d.Version = l.Version
+			m.deps[i] = d
+		}
+		if !found {
+			m.deps = append(m.deps, l)
+		}
+	}
+}
+
 func (m *javaManager) Find(pattern string) []Dependency {
 	var deps []Dependency
 	for _, dep := range m.deps {
@@ -92,26 +125,14 @@ type mavenProject struct {
 	} `xml:"dependencies"`
 }
 
-var (
-	gradleGroovyPatt = regexp.MustCompile(
-		`^(?:implementation|compileOnly|runtimeOnly) ['"]?([^'":]+):([^'":]+):([^'"]+)['"]?$`)
-	gradleKotlinPatt = regexp.MustCompile(
-		`^(?:implementation|compileOnly|runtimeOnly)\(['"]?([^'":]+):([^'":]+):([^'"]+)['"]?\)$`)
-)
-
 func parsePomXML(fsys fs.FS, path string) ([]Dependency, error) {
-	f, err := fsys.Open(filepath.Join(path, "pom.xml"))
-	if err != nil {
+	var project mavenProject
+	if err := parseXML(fsys, path, "pom.xml", &project); err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	defer f.Close()
-	var project mavenProject
-	if err := xml.NewDecoder(f).Decode(&project); err != nil {
-		return nil, err
-	}
 	var deps = make([]Dependency, 0, len(project.Dependencies.Dependency)+1)
 	if project.Parent.GroupID != "" {
 		deps = append(deps, Dependency{
@@ -134,7 +155,148 @@ func parsePomXML(fsys fs.FS, path string) ([]Dependency, error) {
 	return deps, nil
 }
 
-func parseBuildGradle(fsys fs.FS, path, filename string, patt *regexp.Regexp, toolName string) ([]Dependency, error) {
+// gradleVersionCatalog is the TOML version catalog format (gradle/libs.versions.toml).
+// See: https://docs.gradle.org/current/userguide/version_catalogs.html
+type gradleVersionCatalog struct {
+	Versions  map[string]any      `toml:"versions"`
+	Libraries map[string]any      `toml:"libraries"`
+	Bundles   map[string][]string `toml:"bundles"`
+}
+
+// gradleModule identifies a Gradle dependency by its coordinates.
+type gradleModule struct {
+	group, artifact, version string
+}
+
+var (
+	gradleDepPatt      = regexp.MustCompile(`^(\w+)\s*[( ]\s*(.+?)\s*\)?$`)
+	gradlePlatformPatt = regexp.MustCompile(`^(?:enforcedPlatform|platform)\s*\(\s*(.+?)\s*\)$`)
+	gradleQuotedPatt   = regexp.MustCompile(`^['"]([^'"]+)['"]`)
+	gradleCatalogPatt  = regexp.MustCompile(`^libs\.([\w.]+?)(?:\.get\(\))?$`)
+	gradleMapPatt      = regexp.MustCompile(`(group|name|version)\s*[:=]\s*['"]([^'"]+)['"]`)
+)
+
+// gradleConfiguration checks a dependency configuration name (e.g. "implementation").
+// It returns whether the configuration is development-only, and whether it is a known configuration at all.
+func gradleConfiguration(name string) (devOnly, ok bool) {
+	switch name {
+	case "implementation", "api", "runtimeOnly", "compile", "runtime":
+		return false, true
+	case "compileOnly":
+		return true, true
+	}
+	for _, prefix := range []string{"test", "androidTest"} {
+		if rest, found := strings.CutPrefix(name, prefix); found {
+			switch rest {
+			case "Implementation", "CompileOnly", "RuntimeOnly", "Compile", "Runtime":
+				return true, true
+			}
+		}
+	}
+	return false, false
+}
+
+// findGradleVersionCatalog looks for gradle/libs.versions.toml in the path or its parent directories.
+// The catalog is normally defined once in the root project and shared by subprojects.
+func findGradleVersionCatalog(fsys fs.FS, path string) (*gradleVersionCatalog, error) {
+	dir := path
+	for {
+		b, err := fs.ReadFile(fsys, filepath.Join(dir, "gradle", "libs.versions.toml"))
+		if err == nil {
+			var catalog gradleVersionCatalog
+			if err := toml.Unmarshal(b, &catalog); err != nil {
+				return nil, newDiagnostic(filepath.Join(dir, "gradle", "libs.versions.toml"), b,
+					fmt.Errorf("failed to parse as TOML: %w", err))
+			}
+			return &catalog, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return nil, nil
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// version resolves a catalog version declaration, which may be a string, a reference to the [versions] table, or a
+// rich version (e.g. `{ strictly = "1.0" }`).
+func (c *gradleVersionCatalog) version(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if ref, ok := v["ref"].(string); ok {
+			return c.version(c.Versions[ref])
+		}
+		for _, k := range []string{"strictly", "require", "prefer"} {
+			if s, ok := v[k].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// library resolves a catalog library alias into group, artifact and version.
+func (c *gradleVersionCatalog) library(alias string) (group, artifact, version string, ok bool) {
+	for k, v := range c.Libraries {
+		if normalizeGradleAlias(k) != alias {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			parts := strings.SplitN(v, ":", 3)
+			if len(parts) < 2 {
+				return "", "", "", false
+			}
+			if len(parts) == 3 {
+				version = parts[2]
+			}
+			return parts[0], parts[1], version, true
+		case map[string]any:
+			if module, isStr := v["module"].(string); isStr {
+				group, artifact, _ = strings.Cut(module, ":")
+			} else {
+				group, _ = v["group"].(string)
+				artifact, _ = v["name"].(string)
+			}
+			return group, artifact, c.version(v["version"]), group != "" && artifact != ""
+		}
+	}
+	return "", "", "", false
+}
+
+// resolve finds the dependencies referenced by a catalog accessor such as "spring.boot" or "bundles.spring".
+func (c *gradleVersionCatalog) resolve(accessor string) []gradleModule {
+	if bundle, ok := strings.CutPrefix(accessor, "bundles."); ok {
+		var libs []gradleModule
+		for k, aliases := range c.Bundles {
+			if normalizeGradleAlias(k) != bundle {
+				continue
+			}
+			for _, a := range aliases {
+				if g, n, v, ok := c.library(normalizeGradleAlias(a)); ok {
+					libs = append(libs, gradleModule{g, n, v})
+				}
+			}
+		}
+		return libs
+	}
+	if g, n, v, ok := c.library(accessor); ok {
+		return []gradleModule{{g, n, v}}
+	}
+	return nil
+}
+
+// normalizeGradleAlias converts a catalog alias to its accessor form: "spring-boot" and "spring_boot" become
+// "spring.boot".
+func normalizeGradleAlias(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// parseBuildGradle parses a Groovy or Kotlin Gradle build script, resolving version catalog references.
+func parseBuildGradle(fsys fs.FS, path, filename string, catalog *gradleVersionCatalog) ([]Dependency, error) {
 	f, err := fsys.Open(filepath.Join(path, filename))
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -146,14 +308,22 @@ func parseBuildGradle(fsys fs.FS, path, filename string, patt *regexp.Regexp, to
 	var deps []Dependency
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		matches := patt.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
-		if len(matches) > 3 {
+		matches := gradleDepPatt.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if len(matches) != 3 {
+			continue
+		}
+		devOnly, ok := gradleConfiguration(matches[1])
+		if !ok {
+			continue
+		}
+		for _, mod := range parseGradleNotation(matches[2], catalog) {
 			deps = append(deps, Dependency{
-				Vendor:   matches[1],
-				Name:     matches[1] + ":" + matches[2],
-				Version:  matches[3],
-				IsDirect: true, // Dependencies from build.gradle files are direct
-				ToolName: toolName,
+				Vendor:    mod.group,
+				Name:      mod.group + ":" + mod.artifact,
+				Version:   mod.version,
+				IsDirect:  true, // Dependencies from build.gradle files are direct
+				IsDevOnly: devOnly,
+				ToolName:  "gradle",
 			})
 		}
 	}
@@ -163,12 +333,95 @@ func parseBuildGradle(fsys fs.FS, path, filename string, patt *regexp.Regexp, to
 	return deps, nil
 }
 
-func parseBuildGradleGroovy(fsys fs.FS, path string) ([]Dependency, error) {
-	return parseBuildGradle(fsys, path, "build.gradle", gradleGroovyPatt, "gradle")
+// parseGradleNotation parses the argument of a dependency declaration into group, artifact and version.
+// It supports "group:artifact:version" strings, map notation, and version catalog accessors.
+func parseGradleNotation(notation string, catalog *gradleVersionCatalog) []gradleModule {
+	if m := gradlePlatformPatt.FindStringSubmatch(notation); m != nil {
+		notation = m[1]
+	}
+	if m := gradleQuotedPatt.FindStringSubmatch(notation); m != nil {
+		if gradleMapPatt.MatchString(notation) {
+			return parseGradleMapNotation(notation)
+		}
+		parts := strings.SplitN(strings.SplitN(m[1], "@", 2)[0], ":", 4)
+		if len(parts) < 2 {
+			return nil
+		}
+		var version string
+		if len(parts) > 2 {
+			version = parts[2]
+		}
+		return []gradleModule{{parts[0], parts[1], version}}
+	}
+	if m := gradleCatalogPatt.FindStringSubmatch(notation); m != nil {
+		if catalog == nil {
+			return nil
+		}
+		return catalog.resolve(m[1])
+	}
+	return parseGradleMapNotation(notation)
 }
 
-func parseBuildGradleKotlin(fsys fs.FS, path string) ([]Dependency, error) {
-	return parseBuildGradle(fsys, path, "build.gradle.kts", gradleKotlinPatt, "gradle")
+func parseGradleMapNotation(notation string) []gradleModule {
+	var mod gradleModule
+	for _, m := range gradleMapPatt.FindAllStringSubmatch(notation, -1) {
+		switch m[1] {
+		case "group":
+			mod.group = m[2]
+		case "name":
+			mod.artifact = m[2]
+		case "version":
+			mod.version = m[2]
+		}
+	}
+	if mod.group == "" || mod.artifact == "" {
+		return nil
+	}
+	return []gradleModule{mod}
+}
+
+// parseGradleLockfile parses resolved versions from gradle.lockfile.
+// Each line has the format "group:artifact:version=configuration1,configuration2".
+func parseGradleLockfile(fsys fs.FS, path string) ([]Dependency, error) {
+	f, err := fsys.Open(filepath.Join(path, "gradle.lockfile"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var deps []Dependency
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coords, configurations, _ := strings.Cut(line, "=")
+		parts := strings.SplitN(coords, ":", 3)
+		if len(parts) != 3 {
+			continue // e.g. "empty=annotationProcessor"
+		}
+		devOnly := true
+		for conf := range strings.SplitSeq(configurations, ",") {
+			if !strings.HasPrefix(conf, "test") && !strings.HasPrefix(conf, "androidTest") {
+				devOnly = false
+			}
+		}
+		deps = append(deps, Dependency{
+			Vendor:    parts[0],
+			Name:      parts[0] + ":" + parts[1],
+			Version:   parts[2],
+			IsDirect:  false, // Only direct if also declared in a build file
+			IsDevOnly: devOnly,
+			ToolName:  "gradle",
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
 }
 
 func parseBuildSBT(fsys fs.FS, path string) ([]Dependency, error) {
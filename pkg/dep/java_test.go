@@ -136,6 +136,121 @@ func TestGradleKTS(t *testing.T) {
 	}
 }
 
+func TestGradleVersionCatalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"gradle/libs.versions.toml": {Data: []byte(`
+[versions]
+spring-boot = "3.2.1"
+junit = { strictly = "5.10.1" }
+
+[libraries]
+spring-boot-starter-web = { module = "org.springframework.boot:spring-boot-starter-web", version.ref = "spring-boot" }
+spring_boot_starter_data_jpa = { group = "org.springframework.boot", name = "spring-boot-starter-data-jpa" }
+junit-jupiter = { module = "org.junit.jupiter:junit-jupiter", version.ref = "junit" }
+lombok = "org.projectlombok:lombok:1.18.30"
+jackson-databind = "com.fasterxml.jackson.core:jackson-databind:2.16.0"
+jackson-core = "com.fasterxml.jackson.core:jackson-core:2.16.0"
+
+[bundles]
+jackson = ["jackson-databind", "jackson-core"]
+`)},
+		"app/build.gradle.kts": {Data: []byte(`dependencies {
+    implementation(libs.spring.boot.starter.web)
+    implementation(libs.spring.boot.starter.data.jpa)
+    implementation(libs.bundles.jackson)
+    implementation(platform("org.springframework.boot:spring-boot-dependencies:3.2.1"))
+    compileOnly(libs.lombok)
+    testImplementation(libs.junit.jupiter)
+    implementation(group = "com.google.guava", name = "guava", version = "33.0.0-jre")
+}`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeJava, fsys, "app")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	deps := m.Find("*")
+	slices.SortFunc(deps, func(a, b dep.Dependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	assert.Equal(t, []dep.Dependency{
+		{Vendor: "com.fasterxml.jackson.core", Name: "com.fasterxml.jackson.core:jackson-core",
+			Version: "2.16.0", IsDirect: true, ToolName: "gradle"},
+		{Vendor: "com.fasterxml.jackson.core", Name: "com.fasterxml.jackson.core:jackson-databind",
+			Version: "2.16.0", IsDirect: true, ToolName: "gradle"},
+		{Vendor: "com.google.guava", Name: "com.google.guava:guava",
+			Version: "33.0.0-jre", IsDirect: true, ToolName: "gradle"},
+		{Vendor: "org.junit.jupiter", Name: "org.junit.jupiter:junit-jupiter",
+			Version: "5.10.1", IsDirect: true, IsDevOnly: true, ToolName: "gradle"},
+		{Vendor: "org.projectlombok", Name: "org.projectlombok:lombok",
+			Version: "1.18.30", IsDirect: true, IsDevOnly: true, ToolName: "gradle"},
+		{Vendor: "org.springframework.boot", Name: "org.springframework.boot:spring-boot-dependencies",
+			Version: "3.2.1", IsDirect: true, ToolName: "gradle"},
+		{Vendor: "org.springframework.boot", Name: "org.springframework.boot:spring-boot-starter-data-jpa",
+			IsDirect: true, ToolName: "gradle"},
+		{Vendor: "org.springframework.boot", Name: "org.springframework.boot:spring-boot-starter-web",
+			Version: "3.2.1", IsDirect: true, ToolName: "gradle"},
+	}, deps)
+}
+
+func TestGradleLockfile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"build.gradle": {Data: []byte(`
+dependencies {
+    implementation 'org.apache.commons:commons-lang3:3.+'
+    testImplementation 'junit:junit:4.13.2'
+}`)},
+		"gradle.lockfile": {Data: []byte(`# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+org.apache.commons:commons-lang3:3.14.0=compileClasspath,runtimeClasspath,testCompileClasspath,testRuntimeClasspath
+org.hamcrest:hamcrest-core:1.3=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeJava, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	toGet := []struct {
+		name       string
+		dependency dep.Dependency
+		found      bool
+	}{
+		{"org.apache.commons:commons-lang3", dep.Dependency{
+			Vendor:     "org.apache.commons",
+			Name:       "org.apache.commons:commons-lang3",
+			Constraint: "3.+",
+			Version:    "3.14.0",
+			IsDirect:   true,
+			ToolName:   "gradle",
+		}, true},
+		{"junit:junit", dep.Dependency{
+			Vendor:    "junit",
+			Name:      "junit:junit",
+			Version:   "4.13.2",
+			IsDirect:  true,
+			IsDevOnly: true,
+			ToolName:  "gradle",
+		}, true},
+		{"org.hamcrest:hamcrest-core", dep.Dependency{
+			Vendor:    "org.hamcrest",
+			Name:      "org.hamcrest:hamcrest-core",
+			Version:   "1.3",
+			IsDevOnly: true,
+			ToolName:  "gradle",
+		}, true},
+		{name: "empty"},
+	}
+	for _, c := range toGet {
+		d, ok := m.Get(c.name)
+		assert.Equal(t, c.found, ok, c.name)
+		assert.Equal(t, c.dependency, d, c.name)
+	}
+}
+
 func TestMaven(t *testing.T) {
 	fsys := fstest.MapFS{
 		"pom.xml": {Data: []byte(`
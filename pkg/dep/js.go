@@ -1,6 +1,9 @@
 package dep
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"io/fs"
 	"path/filepath"
@@ -9,7 +12,6 @@ import (
 	"sync"
 
 	"github.com/IGLOU-EU/go-wildcard/v2"
-	"gopkg.in/yaml.v3"
 )
 
 type jsManager struct {
@@ -18,7 +20,10 @@ type jsManager struct {
 
 	initOnce sync.Once
 	deps     map[string]Dependency
+	edges    map[string][]string
 	toolName string
+	license  string
+	diagnostics
 }
 
 func newJSManager(fsys fs.FS, path string) Manager {
@@ -51,6 +56,19 @@ func (m *jsManager) Get(name string) (Dependency, bool) {
 	return dep, ok
 }
 
+// License returns the license declared in package.json.
+func (m *jsManager) License() string {
+	return m.license
+}
+
+// Graph returns the dependency graph from package-lock.json or pnpm-lock.yaml.
+func (m *jsManager) Graph() *Graph {
+	if m.edges == nil {
+		return nil
+	}
+	return newGraph(m.deps, m.edges)
+}
+
 func (m *jsManager) vendorName(name string) string {
 	if strings.HasPrefix(name, "@") && strings.Contains(name, "/") {
 		parts := strings.SplitN(name, "/", 2)
@@ -89,13 +107,18 @@ func (m *jsManager) parse() error {
 	if _, ok := files["deno.json"]; ok {
 		m.toolName = "deno"
 		denoDeps, err := parseDenoDeps(m.fsys, m.path)
-		if err != nil {
+		if m.report(err) != nil {
 			return err
 		}
 		for name, dep := range denoDeps {
 			dep.ToolName = m.toolName
 			m.deps[name] = dep
 		}
+		if _, ok := files["deno.lock"]; ok {
+			if err := parseDenoLockDeps(m.fsys, m.path, m.deps); m.report(err) != nil {
+				return err
+			}
+		}
 	}
 
 	// For npm, pnpm, bun, and yarn, always parse package.json first for constraints.
@@ -109,10 +132,11 @@ func (m *jsManager) parse() error {
 			m.toolName = "npm" // default for package.json
 		}
 
-		pkgJsonDeps, err := parsePackageDotJsonDeps(m.fsys, m.path, m.vendorName)
-		if err != nil {
+		pkgJsonDeps, license, err := parsePackageDotJsonDeps(m.fsys, m.path, m.vendorName)
+		if m.report(err) != nil {
 			return err
 		}
+		m.license = license
 		for name, dep := range pkgJsonDeps {
 			dep.ToolName = m.toolName
 			m.deps[name] = dep
@@ -120,17 +144,21 @@ func (m *jsManager) parse() error {
 
 		// Then update Version fields from lock files if present.
 		if _, ok := files["package-lock.json"]; ok {
-			if err := parseNpmLockDeps(m.fsys, m.path, m.deps, m.vendorName, m.toolName); err != nil {
+			m.edges = make(map[string][]string)
+			err := parseNpmLockDeps(m.fsys, m.path, m.deps, m.edges, m.vendorName, m.toolName)
+			if m.report(err) != nil {
 				return err
 			}
 		}
 		if _, ok := files["pnpm-lock.yaml"]; ok {
-			if err := parsePnpmLockDeps(m.fsys, m.path, m.deps, m.vendorName, m.toolName); err != nil {
+			m.edges = make(map[string][]string)
+			err := parsePnpmLockDeps(m.fsys, m.path, m.deps, m.edges, m.vendorName, m.toolName)
+			if m.report(err) != nil {
 				return err
 			}
 		}
 		if _, ok := files["bun.lock"]; ok {
-			if err := parseBunLockDeps(m.fsys, m.path, m.deps, m.vendorName, m.toolName); err != nil {
+			if err := parseBunLockDeps(m.fsys, m.path, m.deps, m.vendorName, m.toolName); m.report(err) != nil {
 				return err
 			}
 		}
@@ -144,12 +172,14 @@ var npmNameVersion = regexp.MustCompile(
 var denoPackageURL = regexp.MustCompile(
 	`^(https://(?:deno\.land/x|esm\.sh)/[^/@]+)@([\dvx*]+(?:[-.](?:[\dx*]+|alpha|beta))*)/$`)
 
-// parsePackageDotJsonDeps parses dependencies from package.json only (not lock files)
-func parsePackageDotJsonDeps(fsys fs.FS, path string, vendorName func(string) string) (map[string]Dependency, error) {
+// parsePackageDotJsonDeps parses dependencies from package.json only (not lock files), and the declared license.
+func parsePackageDotJsonDeps(
+	fsys fs.FS, path string, vendorName func(string) string,
+) (map[string]Dependency, string, error) {
 	var npmManifest packageJSON
 	err := parseJSON(fsys, path, "package.json", &npmManifest)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	deps := map[string]Dependency{}
 	// Add regular dependencies as direct, non-dev
@@ -171,10 +201,10 @@ func parsePackageDotJsonDeps(fsys fs.FS, path string, vendorName func(string) st
 			IsDevOnly:  true,
 		}
 	}
-	return deps, nil
+	return deps, string(npmManifest.License), nil
 }
 
-// parseDenoDeps parses dependencies from deno.json and deno.lock
+// parseDenoDeps parses dependencies from deno.json.
 func parseDenoDeps(fsys fs.FS, path string) (map[string]Dependency, error) {
 	var denoManifest denoJSON
 	if err := parseJSON(fsys, path, "deno.json", &denoManifest); err != nil {
@@ -201,9 +231,14 @@ func parseDenoDeps(fsys fs.FS, path string) (map[string]Dependency, error) {
 			}
 		}
 	}
+	return deps, nil
+}
+
+// parseDenoLockDeps updates deps with versions from deno.lock.
+func parseDenoLockDeps(fsys fs.FS, path string, deps map[string]Dependency) error {
 	var denoLocked denoLock
-	if err := parseJSON(fsys, path, "deno.lock", &denoLocked); err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return nil, err
+	if err := parseJSON(fsys, path, "deno.lock", &denoLocked); err != nil {
+		return err
 	}
 	for nameVersion := range denoLocked.JSR {
 		addDepVersion(deps, nameVersion, func(string) string { return "" }, false, "deno")
@@ -211,65 +246,138 @@ func parseDenoDeps(fsys fs.FS, path string) (map[string]Dependency, error) {
 	for nameVersion := range denoLocked.NPM {
 		addDepVersion(deps, nameVersion, func(string) string { return "" }, false, "deno")
 	}
-	return deps, nil
+	return nil
 }
 
-// parseNpmLockDeps updates deps with versions from package-lock.json
+// parseNpmLockDeps updates deps with versions from package-lock.json, and edges with the names of the packages
+// required by each package.
 func parseNpmLockDeps(
-	fsys fs.FS, path string, deps map[string]Dependency,
+	fsys fs.FS, path string, deps map[string]Dependency, edges map[string][]string,
 	vendorName func(string) string, toolName string,
 ) error {
 	var locked packageLockJSON
 	if err := parseJSON(fsys, path, "package-lock.json", &locked); err != nil {
 		return err
 	}
-	for name, pkg := range locked.Dependencies {
+	addLocked := func(name string, locked Dependency, nested bool) {
 		if d, ok := deps[name]; ok {
+			if nested {
+				// A nested copy of a package does not replace the top-level version
+				return
+			}
 			// Update existing dependency (preserve IsDirect status)
-			d.Version = pkg.Version
+			d.Version = locked.Version
+			d.Source = locked.Source
+			d.Registry = locked.Registry
+			d.Checksum = locked.Checksum
+			d.License = locked.License
 			deps[name] = d
 		} else {
 			// New dependency only found in lock file (indirect)
-			deps[name] = Dependency{
-				Name:     name,
-				Version:  pkg.Version,
-				Vendor:   vendorName(name),
-				IsDirect: false,
-				ToolName: toolName,
-			}
+			locked.Name = name
+			locked.Vendor = vendorName(name)
+			locked.IsDirect = false
+			locked.ToolName = toolName
+			deps[name] = locked
 		}
 	}
-	for name, pkg := range locked.Packages {
-		name = strings.TrimPrefix(name, "node_modules/")
-		if d, ok := deps[name]; ok {
-			// Update existing dependency (preserve IsDirect status)
-			d.Version = pkg.Version
-			deps[name] = d
-		} else {
-			// New dependency only found in lock file (indirect)
-			deps[name] = Dependency{
-				Name:     name,
-				Version:  pkg.Version,
-				Vendor:   vendorName(name),
-				IsDirect: false,
-				ToolName: toolName,
+	var addV1 func(dependencies map[string]packageLockV1Dependency, nested bool)
+	addV1 = func(dependencies map[string]packageLockV1Dependency, nested bool) {
+		for name, pkg := range dependencies {
+			addLocked(name, npmLockedDependency(name, pkg.packageLockEntry, ""), nested)
+			for required := range pkg.Requires {
+				edges[name] = append(edges[name], required)
 			}
+			addV1(pkg.Dependencies, true)
+		}
+	}
+	addV1(locked.Dependencies, false)
+	for key, pkg := range locked.Packages {
+		if key == "" {
+			// The root package
+			continue
+		}
+		// Keys are install paths, e.g. "node_modules/a/node_modules/b" for a nested copy of "b".
+		name := key
+		if i := strings.LastIndex(key, "node_modules/"); i != -1 {
+			name = key[i+len("node_modules/"):]
+		}
+		addLocked(name, npmLockedDependency(name, pkg.packageLockEntry, string(pkg.License)),
+			strings.Count(key, "node_modules/") > 1)
+		for required := range pkg.Dependencies {
+			edges[name] = append(edges[name], required)
+		}
+		for required := range pkg.OptionalDependencies {
+			edges[name] = append(edges[name], required)
 		}
 	}
 	return nil
 }
 
-// parsePnpmLockDeps updates deps with versions from pnpm-lock.yaml
+// npmLockedDependency returns the version, source, registry and checksum of a package in package-lock.json.
+func npmLockedDependency(name string, entry packageLockEntry, license string) Dependency {
+	d := Dependency{Version: entry.Version, Checksum: sriChecksum(entry.Integrity), License: license}
+	resolved := entry.Resolved
+	switch {
+	case entry.Link || strings.HasPrefix(resolved, "file:"):
+		d.Source = "path+" + strings.TrimPrefix(resolved, "file:")
+	case strings.HasPrefix(resolved, "git+"):
+		d.Source = resolved
+	case strings.HasPrefix(resolved, "git:"):
+		d.Source = "git+" + resolved
+	case resolved != "":
+		// Tarballs from a registry have URLs such as "https://registry.npmjs.org/@types/node/-/node-22.0.0.tgz".
+		if i := strings.Index(resolved, "/"+name+"/-/"); i != -1 {
+			d.Registry = resolved[:i+1]
+		} else {
+			d.Source = resolved
+		}
+	}
+	return d
+}
+
+// sriChecksum converts a Subresource Integrity string (e.g. "sha512-<base64>") to the "algorithm:hex" format.
+// Only the first hash is used if there are several.
+func sriChecksum(integrity string) string {
+	first, _, _ := strings.Cut(integrity, " ")
+	algorithm, digest, ok := strings.Cut(first, "-")
+	if !ok {
+		return ""
+	}
+	b, err := base64.StdEncoding.DecodeString(digest)
+	if err != nil {
+		return ""
+	}
+	return algorithm + ":" + hex.EncodeToString(b)
+}
+
+// parsePnpmLockDeps updates deps with versions from pnpm-lock.yaml, and edges with the names of the packages
+// required by each package.
 func parsePnpmLockDeps(
-	fsys fs.FS, path string, deps map[string]Dependency,
+	fsys fs.FS, path string, deps map[string]Dependency, edges map[string][]string,
 	vendorName func(string) string, toolName string,
 ) error {
 	var pnpmLocked pnpmLockYAML
 	if err := parseYAML(fsys, path, "pnpm-lock.yaml", &pnpmLocked); err != nil {
 		return err
 	}
-	for nameVersion := range pnpmLocked.Packages {
-		addDepVersion(deps, nameVersion, vendorName, false, toolName)
+	for key := range pnpmLocked.Packages {
+		addDepVersion(deps, strings.TrimPrefix(key, "/"), vendorName, false, toolName)
+	}
+	// Since lockfile v9, the dependencies of each package are in "snapshots" rather than "packages".
+	for _, packages := range []map[string]pnpmLockPackage{pnpmLocked.Packages, pnpmLocked.Snapshots} {
+		for key, pkg := range packages {
+			matches := npmNameVersion.FindStringSubmatch(strings.TrimPrefix(key, "/"))
+			if len(matches) != 3 {
+				continue
+			}
+			for required := range pkg.Dependencies {
+				edges[matches[1]] = append(edges[matches[1]], required)
+			}
+			for required := range pkg.OptionalDependencies {
+				edges[matches[1]] = append(edges[matches[1]], required)
+			}
+		}
 	}
 	return nil
 }
@@ -386,6 +494,27 @@ func parseMeteorDeps(fsys fs.FS, path string) (map[string]Dependency, error) {
 type packageJSON struct {
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	License         npmLicense        `json:"license"`
+}
+
+// npmLicense is an SPDX expression, or a legacy object with a "type" field.
+// See: https://docs.npmjs.com/cli/v11/configuring-npm/package-json#license
+type npmLicense string
+
+func (l *npmLicense) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = npmLicense(s)
+		return nil
+	}
+	var legacy struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &legacy); err != nil {
+		return err
+	}
+	*l = npmLicense(legacy.Type)
+	return nil
 }
 
 type denoJSON struct {
@@ -394,18 +523,39 @@ type denoJSON struct {
 
 type packageLockJSON struct {
 	// Version 1 (see https://docs.npmjs.com/cli/v11/configuring-npm/package-lock-json#dependencies)
-	Dependencies map[string]struct {
-		Version string `json:"version"`
-	} `json:"dependencies"`
+	Dependencies map[string]packageLockV1Dependency `json:"dependencies"`
 
 	// Versions 2 and 3
 	Packages map[string]struct {
-		Version string `json:"version"`
+		packageLockEntry
+		License              npmLicense        `json:"license"`
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
 	} `json:"Packages"`
 }
 
+type packageLockV1Dependency struct {
+	packageLockEntry
+	Requires     map[string]string                  `json:"requires"`
+	Dependencies map[string]packageLockV1Dependency `json:"dependencies"`
+}
+
+// packageLockEntry contains the fields common to all versions of package-lock.json.
+type packageLockEntry struct {
+	Version   string `json:"version"`
+	Resolved  string `json:"resolved"`  // The URL of the tarball, or a git or file location
+	Integrity string `json:"integrity"` // A Subresource Integrity hash
+	Link      bool   `json:"link"`      // True for a symbolic link to a local package (the resolved path)
+}
+
 type pnpmLockYAML struct {
-	Packages map[string]yaml.Node `yaml:"packages"`
+	Packages  map[string]pnpmLockPackage `yaml:"packages"`
+	Snapshots map[string]pnpmLockPackage `yaml:"snapshots"`
+}
+
+type pnpmLockPackage struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
 }
 
 type bunLock struct {
@@ -40,6 +40,10 @@ func TestNPM(t *testing.T) {
 			Version:    "5.14.1",
 			IsDirect:   true,
 			ToolName:   "npm",
+			Registry:   "https://registry.npmjs.org/",
+			Checksum: "sha512:c6e9886c39746e4fc77adfbdc034044cd487b64a1b5da794413722c336d3e36456ff32283e42938cdcdd0eb58" +
+				"e073ce9991d1153fa849cb6b4b9d0e1cd2d355b",
+			License: "MIT",
 		}}},
 	}
 	for _, c := range cases {
@@ -49,4 +53,42 @@ func TestNPM(t *testing.T) {
 		})
 		assert.Equal(t, c.dependencies, deps)
 	}
+
+	assert.Equal(t, "ISC", m.(dep.LicenseManager).License())
+}
+
+func TestNPM_Sources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"package.json": {Data: []byte(
+			`{"dependencies": {"@acme/ui": "^1.0", "lib": "file:../lib", "fork": "github:a/fork"}}`)},
+		"package-lock.json": {Data: []byte(`{"lockfileVersion": 3, "packages": {
+			"": {},
+			"node_modules/@acme/ui": {
+				"version": "1.2.0",
+				"resolved": "https://npm.acme.example/repository/npm/@acme/ui/-/ui-1.2.0.tgz",
+				"integrity": "sha1-AAECAwQFBgcICQoLDA0ODxAREhM="
+			},
+			"node_modules/lib": {"resolved": "../lib", "link": true},
+			"node_modules/fork": {
+				"version": "2.0.0",
+				"resolved": "git+ssh://git@github.com/a/fork.git#0123456789abcdef0123456789abcdef01234567"
+			}
+		}}`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeJavaScript, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	ui, _ := m.Get("@acme/ui")
+	assert.Equal(t, "https://npm.acme.example/repository/npm/", ui.Registry)
+	assert.Equal(t, "sha1:000102030405060708090a0b0c0d0e0f10111213", ui.Checksum)
+	assert.Empty(t, ui.Source)
+
+	lib, _ := m.Get("lib")
+	assert.Equal(t, "path+../lib", lib.Source)
+
+	fork, _ := m.Get("fork")
+	assert.Equal(t, "git+ssh://git@github.com/a/fork.git#0123456789abcdef0123456789abcdef01234567", fork.Source)
+	assert.Empty(t, fork.Registry)
 }
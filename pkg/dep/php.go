@@ -1,6 +1,7 @@
 package dep
 
 import (
+	"encoding/json"
 	"errors"
 	"io/fs"
 	"strings"
@@ -17,18 +18,114 @@ type phpManager struct {
 	composerLock composerLock
 
 	initOnce sync.Once
+	diagnostics
 }
 
 type composerJSON struct {
 	Require    map[string]string `json:"require"`
 	RequireDev map[string]string `json:"require-dev"`
+	License    composerLicense   `json:"license"`
 }
 
 type composerLock struct {
-	Packages []struct {
-		Name    string `json:"name"`
-		Version string `json:"version"`
-	} `json:"packages"`
+	Packages    []composerLockPackage `json:"packages"`
+	PackagesDev []composerLockPackage `json:"packages-dev"`
+	Platform    composerPlatform      `json:"platform"`
+	PlatformDev composerPlatform      `json:"platform-dev"`
+}
+
+type composerLockPackage struct {
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Require         map[string]string `json:"require"`
+	License         composerLicense   `json:"license"`
+	Source          composerSource    `json:"source"`
+	Dist            composerSource    `json:"dist"`
+	NotificationURL string            `json:"notification-url"`
+}
+
+// composerSource is the "source" or "dist" of a locked package.
+type composerSource struct {
+	Type      string `json:"type"` // e.g. "git", "zip" or "path"
+	URL       string `json:"url"`
+	Reference string `json:"reference"`
+	Shasum    string `json:"shasum"`
+}
+
+// source returns the source of a package that was not installed from a Composer repository.
+func (p composerLockPackage) source() string {
+	switch {
+	case p.Dist.Type == "path":
+		return "path+" + p.Dist.URL
+	case p.NotificationURL != "":
+		return ""
+	case p.Source.Type == "git" && p.Source.URL != "":
+		s := "git+" + p.Source.URL
+		if p.Source.Reference != "" {
+			s += "#" + p.Source.Reference
+		}
+		return s
+	}
+	return ""
+}
+
+// registry returns the URL of the Composer repository that a package was installed from, which is only recorded in
+// its "notification-url", e.g. "https://packagist.org/downloads/".
+func (p composerLockPackage) registry() string {
+	return strings.TrimSuffix(p.NotificationURL, "downloads/")
+}
+
+func (p composerLockPackage) checksum() string {
+	if p.Dist.Shasum == "" {
+		return ""
+	}
+	return "sha1:" + p.Dist.Shasum
+}
+
+// composerLicense is a list of alternative licenses, which may also be a single string in composer.json.
+// See: https://getcomposer.org/doc/04-schema.md#license
+type composerLicense []string
+
+func (l *composerLicense) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = composerLicense{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+func (l composerLicense) String() string {
+	return joinLicenses(l)
+}
+
+// composerPlatform is a map of platform requirements, which Composer encodes as an empty list when there are none.
+type composerPlatform map[string]string
+
+func (p *composerPlatform) UnmarshalJSON(b []byte) error {
+	if string(b) == "[]" {
+		*p = nil
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*p = m
+	return nil
+}
+
+// isComposerPlatformPackage checks if a package name refers to a platform requirement rather than a package.
+// See: https://getcomposer.org/doc/01-basic-usage.md#platform-packages
+func isComposerPlatformPackage(name string) bool {
+	return name == "php" || strings.HasPrefix(name, "php-") ||
+		strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-") ||
+		name == "hhvm" || name == "composer" || strings.HasPrefix(name, "composer-")
 }
 
 func newPHPManager(fsys fs.FS, path string) Manager {
@@ -41,76 +138,181 @@ func newPHPManager(fsys fs.FS, path string) Manager {
 func (m *phpManager) Init() error {
 	var err error
 	m.initOnce.Do(func() {
-		err = parseJSON(m.fsys, m.path, "composer.json", &m.composerJSON)
-		if err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				err = nil
-			}
-			return
-		}
-		err = parseJSON(m.fsys, m.path, "composer.lock", &m.composerLock)
-		if err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				err = nil
-			}
-			return
-		}
+		err = m.parse()
 	})
 	return err
 }
 
+func (m *phpManager) parse() error {
+	err := parseJSON(m.fsys, m.path, "composer.json", &m.composerJSON)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if m.report(err) != nil {
+		return err
+	}
+	err = parseJSON(m.fsys, m.path, "composer.lock", &m.composerLock)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return m.report(err)
+}
+
+// Platform returns the platform requirements, such as "php" and "ext-*", from composer.json and composer.lock.
+func (m *phpManager) Platform() []Dependency {
+	var deps []Dependency
+	seen := make(map[string]struct{})
+	add := func(requirements map[string]string, devOnly bool) {
+		for name, constraint := range requirements {
+			if _, ok := seen[name]; ok || !isComposerPlatformPackage(name) {
+				continue
+			}
+			seen[name] = struct{}{}
+			deps = append(deps, Dependency{
+				Name:       name,
+				Constraint: constraint,
+				IsDirect:   true,
+				IsDevOnly:  devOnly,
+				ToolName:   "composer",
+			})
+		}
+	}
+	add(m.composerJSON.Require, false)
+	add(m.composerLock.Platform, false)
+	add(m.composerJSON.RequireDev, true)
+	add(m.composerLock.PlatformDev, true)
+	return deps
+}
+
+// Find returns the packages matching a pattern. Platform requirements are listed separately, by Platform.
 func (m *phpManager) Find(pattern string) []Dependency {
 	var deps []Dependency
 	// Add regular dependencies (non-dev)
 	for name, constraint := range m.composerJSON.Require {
-		if wildcard.Match(pattern, name) {
-			parts := strings.SplitN(name, "/", 2)
-			var vendor string
-			if len(parts) == 2 {
-				vendor = parts[0]
-			}
+		if wildcard.Match(pattern, name) && !isComposerPlatformPackage(name) {
+			locked, _ := m.getLocked(name)
 			deps = append(deps, Dependency{
-				Vendor:     vendor,
+				Vendor:     composerVendor(name),
 				Name:       name,
 				Constraint: constraint,
-				Version:    m.getLockedVersion(name),
+				Version:    locked.Version,
 				IsDirect:   true, // Dependencies from composer.json are direct
 				ToolName:   "composer",
+				Source:     locked.source(),
+				Registry:   locked.registry(),
+				Checksum:   locked.checksum(),
+				License:    locked.License.String(),
 			})
 		}
 	}
 	// Add dev dependencies
 	for name, constraint := range m.composerJSON.RequireDev {
-		if wildcard.Match(pattern, name) {
-			parts := strings.SplitN(name, "/", 2)
-			var vendor string
-			if len(parts) == 2 {
-				vendor = parts[0]
-			}
+		if wildcard.Match(pattern, name) && !isComposerPlatformPackage(name) {
+			locked, _ := m.getLocked(name)
 			deps = append(deps, Dependency{
-				Vendor:     vendor,
+				Vendor:     composerVendor(name),
 				Name:       name,
 				Constraint: constraint,
-				Version:    m.getLockedVersion(name),
+				Version:    locked.Version,
 				IsDirect:   true, // Dependencies from composer.json are direct
 				IsDevOnly:  true,
 				ToolName:   "composer",
+				Source:     locked.source(),
+				Registry:   locked.registry(),
+				Checksum:   locked.checksum(),
+				License:    locked.License.String(),
 			})
 		}
 	}
+	// Add transitive dependencies from the lock file
+	for _, p := range m.lockedPackages() {
+		if !wildcard.Match(pattern, p.Name) {
+			continue
+		}
+		if _, ok := m.composerJSON.Require[p.Name]; ok {
+			continue
+		}
+		if _, ok := m.composerJSON.RequireDev[p.Name]; ok {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Vendor:    composerVendor(p.Name),
+			Name:      p.Name,
+			Version:   p.Version,
+			IsDirect:  false,
+			IsDevOnly: p.isDev,
+			ToolName:  "composer",
+			Source:    p.source(),
+			Registry:  p.registry(),
+			Checksum:  p.checksum(),
+			License:   p.License.String(),
+		})
+	}
 	return deps
 }
 
-func (m *phpManager) getLockedVersion(packageName string) string {
+type lockedComposerPackage struct {
+	composerLockPackage
+	isDev bool
+}
+
+// lockedPackages returns all packages from composer.lock, including development packages.
+func (m *phpManager) lockedPackages() []lockedComposerPackage {
+	packages := make([]lockedComposerPackage, 0, len(m.composerLock.Packages)+len(m.composerLock.PackagesDev))
 	for _, p := range m.composerLock.Packages {
+		packages = append(packages, lockedComposerPackage{p, false})
+	}
+	for _, p := range m.composerLock.PackagesDev {
+		packages = append(packages, lockedComposerPackage{p, true})
+	}
+	return packages
+}
+
+func (m *phpManager) getLocked(packageName string) (lockedComposerPackage, bool) {
+	for _, p := range m.lockedPackages() {
 		if p.Name == packageName {
-			return p.Version
+			return p, true
 		}
 	}
+	return lockedComposerPackage{}, false
+}
+
+// License returns the license declared in composer.json.
+func (m *phpManager) License() string {
+	return m.composerJSON.License.String()
+}
+
+// Graph returns the dependency graph from composer.lock.
+func (m *phpManager) Graph() *Graph {
+	packages := m.lockedPackages()
+	if len(packages) == 0 {
+		return nil
+	}
+	deps := make(map[string]Dependency)
+	for _, d := range m.Find("*") {
+		deps[d.Name] = d
+	}
+	edges := make(map[string][]string, len(packages))
+	for _, p := range packages {
+		for name := range p.Require {
+			// Platform requirements are not nodes in the graph, so they are ignored.
+			edges[p.Name] = append(edges[p.Name], name)
+		}
+	}
+	return newGraph(deps, edges)
+}
+
+func composerVendor(name string) string {
+	if vendor, _, ok := strings.Cut(name, "/"); ok {
+		return vendor
+	}
 	return ""
 }
 
 func (m *phpManager) Get(name string) (Dependency, bool) {
+	if isComposerPlatformPackage(name) {
+		return Dependency{}, false
+	}
 	packageName := name
 	constraint, inRequire := m.composerJSON.Require[name]
 	constraintDev, inRequireDev := m.composerJSON.RequireDev[name]
@@ -121,23 +323,24 @@ func (m *phpManager) Get(name string) (Dependency, bool) {
 	}
 
 	// Must be in either require, require-dev, or lock file
-	if !inRequire && !inRequireDev && m.getLockedVersion(packageName) == "" {
+	locked, inLock := m.getLocked(packageName)
+	if !inRequire && !inRequireDev && !inLock {
 		return Dependency{}, false
 	}
 
-	var vendor string
-	if strings.Contains(name, "/") {
-		parts := strings.SplitN(name, "/", 2)
-		vendor = parts[0]
-	}
-
+	isDirect := inRequire || inRequireDev // Direct if found in composer.json
 	return Dependency{
-		Vendor:     vendor,
+		Vendor:     composerVendor(name),
 		Name:       name,
 		Constraint: constraint,
-		Version:    m.getLockedVersion(packageName),
-		IsDirect:   inRequire || inRequireDev,  // Direct if found in composer.json
-		IsDevOnly:  inRequireDev && !inRequire, // Dev-only if only in require-dev
-		ToolName:   "composer",
+		Version:    locked.Version,
+		IsDirect:   isDirect,
+		// Dev-only if only in require-dev, or only in the lock file's packages-dev
+		IsDevOnly: (inRequireDev && !inRequire) || (!isDirect && locked.isDev),
+		ToolName:  "composer",
+		Source:    locked.source(),
+		Registry:  locked.registry(),
+		Checksum:  locked.checksum(),
+		License:   locked.License.String(),
 	}, true
 }
@@ -1,13 +1,14 @@
 package dep_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/upsun/whatsun/internal/fsdir"
 	"github.com/upsun/whatsun/pkg/dep"
 )
 
@@ -30,8 +31,9 @@ func TestPHP(t *testing.T) {
 		},
 	}
 
-	m, err := dep.GetCachedManager(dep.ManagerTypePHP, fsdir.New(fsys, "."))
+	m, err := dep.GetManager(dep.ManagerTypePHP, fsys, ".")
 	require.NoError(t, err)
+	require.NoError(t, m.Init())
 
 	toFind := []struct {
 		pattern      string
@@ -65,12 +67,7 @@ func TestPHP(t *testing.T) {
 			IsDirect:   true,
 			ToolName:   "composer",
 		}, found: true},
-		{name: "php", dependency: dep.Dependency{
-			Name:       "php",
-			Constraint: ">=8.2",
-			IsDirect:   true,
-			ToolName:   "composer",
-		}, found: true},
+		{name: "php"},
 	}
 	for _, c := range toGet {
 		d, ok := m.Get(c.name)
@@ -78,3 +75,124 @@ func TestPHP(t *testing.T) {
 		assert.Equal(t, c.dependency, d)
 	}
 }
+
+func TestPHPLockFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"composer.json": {Data: []byte(`{
+			"license": ["MIT", "GPL-3.0-or-later"],
+			"require": {
+				"php": ">=8.2",
+				"ext-intl": "*",
+				"laravel/framework": "^11.0"
+			},
+			"require-dev": {
+				"phpunit/phpunit": "^11.0"
+			}}`),
+		},
+		"composer.lock": {Data: []byte(`{
+			"packages": [
+				{"name": "laravel/framework", "version": "v11.9.2", "license": ["MIT"]},
+				{"name": "symfony/console", "version": "v7.1.1", "license": ["MIT"]}
+			],
+			"packages-dev": [
+				{"name": "phpunit/phpunit", "version": "11.2.2", "license": ["BSD-3-Clause"]},
+				{"name": "sebastian/diff", "version": "6.0.1", "license": ["BSD-3-Clause"]}
+			],
+			"platform": {"php": ">=8.2", "ext-intl": "*"},
+			"platform-dev": []
+			}`),
+		},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypePHP, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	deps := m.Find("s*/*")
+	slices.SortFunc(deps, func(a, b dep.Dependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	assert.Equal(t, []dep.Dependency{
+		{Vendor: "sebastian", Name: "sebastian/diff", Version: "6.0.1", IsDevOnly: true, ToolName: "composer",
+			License: "BSD-3-Clause"},
+		{Vendor: "symfony", Name: "symfony/console", Version: "v7.1.1", ToolName: "composer", License: "MIT"},
+	}, deps)
+
+	assert.Empty(t, m.Find("php"))
+	assert.Empty(t, m.Find("ext-*"))
+	_, ok := m.Get("ext-intl")
+	assert.False(t, ok)
+
+	d, ok := m.Get("phpunit/phpunit")
+	assert.True(t, ok)
+	assert.Equal(t, dep.Dependency{
+		Vendor:     "phpunit",
+		Name:       "phpunit/phpunit",
+		Constraint: "^11.0",
+		Version:    "11.2.2",
+		IsDirect:   true,
+		IsDevOnly:  true,
+		ToolName:   "composer",
+		License:    "BSD-3-Clause",
+	}, d)
+
+	d, ok = m.Get("sebastian/diff")
+	assert.True(t, ok)
+	assert.True(t, d.IsDevOnly)
+	assert.False(t, d.IsDirect)
+
+	pm, ok := m.(dep.PlatformManager)
+	require.True(t, ok)
+	platform := pm.Platform()
+	slices.SortFunc(platform, func(a, b dep.Dependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	assert.Equal(t, []dep.Dependency{
+		{Name: "ext-intl", Constraint: "*", IsDirect: true, ToolName: "composer"},
+		{Name: "php", Constraint: ">=8.2", IsDirect: true, ToolName: "composer"},
+	}, platform)
+
+	assert.Equal(t, "MIT OR GPL-3.0-or-later", m.(dep.LicenseManager).License())
+}
+
+func TestPHPLockFileSources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"composer.json": {Data: []byte(`{"require": {"acme/billing": "^2.0", "acme/shared": "*", "acme/fork": "dev-main"}}`)},
+		"composer.lock": {Data: []byte(`{"packages": [
+			{
+				"name": "acme/billing",
+				"version": "2.1.0",
+				"source": {"type": "git", "url": "https://git.acme.example/billing.git", "reference": "abc123"},
+				"dist": {"type": "zip", "url": "https://repo.packagist.com/acme/dists/acme/billing/2.1.0/abc.zip",
+					"reference": "abc123", "shasum": "5d41402abc4b2a76b9719d911017c592"},
+				"notification-url": "https://repo.packagist.com/acme/downloads/"
+			},
+			{
+				"name": "acme/shared",
+				"version": "dev-main",
+				"dist": {"type": "path", "url": "../shared", "reference": "def456"}
+			},
+			{
+				"name": "acme/fork",
+				"version": "dev-main",
+				"source": {"type": "git", "url": "https://github.com/acme/fork.git", "reference": "0a1b2c"}
+			}
+		]}`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypePHP, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	d, _ := m.Get("acme/billing")
+	assert.Empty(t, d.Source)
+	assert.Equal(t, "https://repo.packagist.com/acme/", d.Registry)
+	assert.Equal(t, "sha1:5d41402abc4b2a76b9719d911017c592", d.Checksum)
+
+	d, _ = m.Get("acme/shared")
+	assert.Equal(t, "path+../shared", d.Source)
+	assert.Empty(t, d.Registry)
+
+	d, _ = m.Get("acme/fork")
+	assert.Equal(t, "git+https://github.com/acme/fork.git#0a1b2c", d.Source)
+}
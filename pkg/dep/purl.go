@@ -0,0 +1,108 @@
+package dep
+
+import (
+	"net/url"
+	"strings"
+)
+
+// PackageURL returns the package URL (purl) of a dependency found by a manager of the given type, e.g.
+// "pkg:npm/%40babel/core@7.26.0" or "pkg:maven/org.springframework/spring-core@6.2.1".
+// The version is the resolved version, if known. Git sources are added as a "vcs_url" qualifier.
+// See: https://github.com/package-url/purl-spec
+func PackageURL(managerType string, d Dependency) string {
+	purlType, namespace, name := purlComponents(managerType, d)
+
+	var b strings.Builder
+	b.WriteString("pkg:" + purlType + "/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			b.WriteString(purlEscape(segment) + "/")
+		}
+	}
+	b.WriteString(purlEscape(name))
+	if d.Version != "" {
+		b.WriteString("@" + purlEscape(d.Version))
+	}
+	if strings.HasPrefix(d.Source, "git+") {
+		b.WriteString("?vcs_url=" + url.QueryEscape(d.Source))
+	}
+	if managerType == ManagerTypeGitHubActions {
+		if parts := strings.SplitN(d.Name, "/", 3); len(parts) == 3 {
+			b.WriteString("#" + parts[2])
+		}
+	}
+	return b.String()
+}
+
+// purlComponents returns the purl type, namespace and name of a dependency.
+func purlComponents(managerType string, d Dependency) (purlType, namespace, name string) {
+	name = d.Name
+	switch managerType {
+	case ManagerTypeJavaScript:
+		if n, ok := strings.CutPrefix(name, "npm:"); ok {
+			name = n
+		} else if strings.Contains(name, ":") {
+			// Deno's JSR packages and URL imports have no purl type.
+			return "generic", "", name
+		}
+		namespace, name = splitLast(name, "/")
+		return "npm", namespace, name
+	case ManagerTypePHP:
+		return "composer", d.Vendor, strings.TrimPrefix(name, d.Vendor+"/")
+	case ManagerTypePython:
+		return "pypi", "", normalizePythonName(name)
+	case ManagerTypeRuby:
+		return "gem", "", name
+	case ManagerTypeRust:
+		return "cargo", "", name
+	case ManagerTypeGo:
+		namespace, name = splitLast(name, "/")
+		return "golang", namespace, name
+	case ManagerTypeJava:
+		if group, artifact, ok := strings.Cut(name, ":"); ok {
+			return "maven", group, artifact
+		}
+		return "maven", "", name
+	case ManagerTypeDotnet:
+		return "nuget", "", name
+	case ManagerTypeElixir:
+		return "hex", "", name
+	case ManagerTypeDart:
+		return "pub", "", name
+	case ManagerTypeSwift:
+		if d.ToolName == "cocoapods" {
+			return "cocoapods", "", name
+		}
+		// SwiftPM packages are identified by their repository, e.g. "github.com/apple/swift-nio".
+		if u, err := url.Parse(strings.TrimPrefix(d.Source, "git+")); err == nil && u.Host != "" {
+			namespace, _ = splitLast(u.Host+strings.TrimSuffix(u.Path, ".git"), "/")
+		}
+		return "swift", namespace, name
+	case ManagerTypeCpp:
+		if d.ToolName == "conan" {
+			return "conan", "", name
+		}
+	case ManagerTypeDocker:
+		namespace, name = splitLast(name, "/")
+		return "docker", namespace, name
+	case ManagerTypeGitHubActions:
+		// Actions may be in a subdirectory of a repository, e.g. "github/codeql-action/init".
+		owner, repo, _ := strings.Cut(name, "/")
+		repo, _, _ = strings.Cut(repo, "/")
+		return "github", owner, repo
+	}
+	return "generic", "", name
+}
+
+// splitLast splits a name at the last separator, e.g. "@babel/core" to "@babel" and "core".
+func splitLast(name, sep string) (before, after string) {
+	if i := strings.LastIndex(name, sep); i != -1 {
+		return name[:i], name[i+len(sep):]
+	}
+	return "", name
+}
+
+// purlEscape percent-encodes a purl segment.
+func purlEscape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+}
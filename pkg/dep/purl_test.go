@@ -0,0 +1,43 @@
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestPackageURL(t *testing.T) {
+	cases := []struct {
+		managerType string
+		dependency  dep.Dependency
+		purl        string
+	}{
+		{"js", dep.Dependency{Vendor: "babel", Name: "@babel/core", Version: "7.26.0"}, "pkg:npm/%40babel/core@7.26.0"},
+		{"js", dep.Dependency{Name: "express"}, "pkg:npm/express"},
+		{"js", dep.Dependency{Name: "npm:chalk", Version: "5.4.1"}, "pkg:npm/chalk@5.4.1"},
+		{"php", dep.Dependency{Vendor: "symfony", Name: "symfony/console", Version: "v7.2.1"},
+			"pkg:composer/symfony/console@v7.2.1"},
+		{"python", dep.Dependency{Name: "Django_Rest.Framework", Version: "3.15.2"},
+			"pkg:pypi/django-rest-framework@3.15.2"},
+		{"go", dep.Dependency{Name: "github.com/gin-gonic/gin", Version: "v1.10.0"},
+			"pkg:golang/github.com/gin-gonic/gin@v1.10.0"},
+		{"java", dep.Dependency{Vendor: "org.springframework", Name: "org.springframework:spring-core", Version: "6.2.1"},
+			"pkg:maven/org.springframework/spring-core@6.2.1"},
+		{"ruby", dep.Dependency{Name: "rails", Version: "8.0.1", Source: "git+https://github.com/rails/rails.git#main"},
+			"pkg:gem/rails@8.0.1?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Frails%2Frails.git%23main"},
+		{"swift", dep.Dependency{Name: "swift-nio", Version: "2.79.0", ToolName: "swiftpm",
+			Source: "git+https://github.com/apple/swift-nio.git"},
+			"pkg:swift/github.com/apple/swift-nio@2.79.0?vcs_url=git%2Bhttps%3A%2F%2Fgithub.com%2Fapple%2Fswift-nio.git"},
+		{"swift", dep.Dependency{Name: "Alamofire", Version: "5.10.2", ToolName: "cocoapods"},
+			"pkg:cocoapods/Alamofire@5.10.2"},
+		{"docker", dep.Dependency{Name: "bitnami/redis", Version: "7.4"}, "pkg:docker/bitnami/redis@7.4"},
+		{"github-actions", dep.Dependency{Name: "github/codeql-action/init", Version: "v3"},
+			"pkg:github/github/codeql-action@v3#init"},
+		{"terraform", dep.Dependency{Name: "hashicorp/aws", Version: "5.82.2"}, "pkg:generic/hashicorp%2Faws@5.82.2"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.purl, dep.PackageURL(c.managerType, c.dependency))
+	}
+}
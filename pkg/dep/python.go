@@ -21,6 +21,9 @@ type pythonManager struct {
 
 	initOnce     sync.Once
 	dependencies []Dependency
+	edges        map[string][]string
+	license      string
+	diagnostics
 }
 
 func newPythonManager(fsys fs.FS, path string) Manager {
@@ -58,7 +61,7 @@ func (m *pythonManager) parseFile(
 	defer f.Close()
 	deps, err := parseFunc(f, toolName)
 	if err != nil {
-		return err
+		return m.report(newDiagnostic(filepath.Join(m.path, filename), nil, err))
 	}
 	m.dependencies = append(m.dependencies, deps...)
 	return nil
@@ -76,6 +79,9 @@ func (m *pythonManager) mergeResolvedVersions() {
 			}
 			if dep.Version != "" {
 				existing.Version = dep.Version
+				existing.Source = dep.Source
+				existing.Registry = dep.Registry
+				existing.Checksum = dep.Checksum
 			}
 			merged[dep.Name] = existing
 		} else {
@@ -142,12 +148,14 @@ func (m *pythonManager) detectPyprojectTool() string {
 }
 
 func (m *pythonManager) parse() error {
+	m.license = m.parsePyprojectLicense()
+
 	switch tool := m.determineTool(); tool {
 	case "uv":
 		if err := m.parseFile("pyproject.toml", parsePyprojectTOML, tool); err != nil {
 			return err
 		}
-		if err := m.parseFile("uv.lock", parseUvLock, tool); err != nil {
+		if err := m.parseFile("uv.lock", m.parseUvLock, tool); err != nil {
 			return err
 		}
 		m.mergeResolvedVersions()
@@ -156,7 +164,7 @@ func (m *pythonManager) parse() error {
 		if err := m.parseFile("pyproject.toml", parsePyprojectTOML, tool); err != nil {
 			return err
 		}
-		if err := m.parseFile("poetry.lock", parsePoetryLock, tool); err != nil {
+		if err := m.parseFile("poetry.lock", m.parsePoetryLock, tool); err != nil {
 			return err
 		}
 		m.mergeResolvedVersions()
@@ -180,6 +188,50 @@ func (m *pythonManager) parse() error {
 	return nil
 }
 
+// parsePyprojectLicense reads the license declared in pyproject.toml, as an SPDX expression (PEP 639), a table with
+// the license text (PEP 621), or a Poetry setting.
+func (m *pythonManager) parsePyprojectLicense() string {
+	f, err := m.fsys.Open(filepath.Join(m.path, "pyproject.toml"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	type PyProjectLicense struct {
+		Project struct {
+			License toml.Primitive `toml:"license"`
+		} `toml:"project"`
+		Tool struct {
+			Poetry struct {
+				License string `toml:"license"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+
+	var pyProject PyProjectLicense
+	md, err := toml.NewDecoder(f).Decode(&pyProject)
+	if err != nil {
+		return ""
+	}
+	var license string
+	if err := md.PrimitiveDecode(pyProject.Project.License, &license); err != nil {
+		var table struct {
+			Text string `toml:"text"`
+		}
+		_ = md.PrimitiveDecode(pyProject.Project.License, &table) // A license file is not read
+		license = strings.TrimSpace(table.Text)
+	}
+	if license == "" {
+		license = pyProject.Tool.Poetry.License
+	}
+	return license
+}
+
+// License returns the license declared in pyproject.toml.
+func (m *pythonManager) License() string {
+	return m.license
+}
+
 func (m *pythonManager) Find(pattern string) []Dependency {
 	var deps []Dependency
 	for _, dep := range m.dependencies {
@@ -190,6 +242,18 @@ func (m *pythonManager) Find(pattern string) []Dependency {
 	return deps
 }
 
+// Graph returns the dependency graph from uv.lock or poetry.lock.
+func (m *pythonManager) Graph() *Graph {
+	if m.edges == nil {
+		return nil
+	}
+	deps := make(map[string]Dependency, len(m.dependencies))
+	for _, d := range m.dependencies {
+		deps[d.Name] = d
+	}
+	return newGraph(deps, m.edges)
+}
+
 func (m *pythonManager) Get(name string) (Dependency, bool) {
 	for _, dep := range m.dependencies {
 		if dep.Name == name {
@@ -440,11 +504,23 @@ func parsePyprojectTOML(r io.Reader, toolName string) ([]Dependency, error) {
 	return dependencies, nil
 }
 
-// parseUvLock parses a uv.lock TOML file and extracts dependencies
-func parseUvLock(r io.Reader, toolName string) ([]Dependency, error) {
+// parseUvLock parses a uv.lock TOML file and extracts dependencies, and the graph edges between them
+func (m *pythonManager) parseUvLock(r io.Reader, toolName string) ([]Dependency, error) {
+	type UvDependency struct {
+		Name string `toml:"name"`
+	}
+	type UvArtifact struct {
+		Hash string `toml:"hash"` // e.g. "sha256:4a5f…"
+	}
 	type UvPackage struct {
-		Name    string `toml:"name"`
-		Version string `toml:"version"`
+		Name                 string                    `toml:"name"`
+		Version              string                    `toml:"version"`
+		Source               map[string]string         `toml:"source"`
+		Sdist                *UvArtifact               `toml:"sdist"`
+		Wheels               []UvArtifact              `toml:"wheels"`
+		Dependencies         []UvDependency            `toml:"dependencies"`
+		OptionalDependencies map[string][]UvDependency `toml:"optional-dependencies"`
+		DevDependencies      map[string][]UvDependency `toml:"dev-dependencies"`
 	}
 	type UvLock struct {
 		Packages []UvPackage `toml:"package"`
@@ -456,22 +532,60 @@ func parseUvLock(r io.Reader, toolName string) ([]Dependency, error) {
 		return nil, err
 	}
 	var dependencies []Dependency
+	m.edges = make(map[string][]string)
 	for _, pkg := range lock.Packages {
-		dependencies = append(dependencies, Dependency{
+		d := Dependency{
 			Name:     pkg.Name,
 			Version:  pkg.Version,
 			IsDirect: false, // Lock files contain both direct and indirect deps
 			ToolName: toolName,
-		})
+		}
+		// The source is a table with one key, e.g. { registry = "https://pypi.org/simple" } or { editable = "." }.
+		switch {
+		case pkg.Source["registry"] != "":
+			d.Registry = pkg.Source["registry"]
+		case pkg.Source["git"] != "":
+			d.Source = "git+" + pkg.Source["git"]
+		case pkg.Source["url"] != "":
+			d.Source = pkg.Source["url"]
+		default:
+			for _, key := range []string{"path", "directory", "editable", "virtual"} {
+				if path, ok := pkg.Source[key]; ok {
+					d.Source = "path+" + path
+					break
+				}
+			}
+		}
+		// The checksum of the source distribution is used, or of the wheel if there is only one.
+		if pkg.Sdist != nil {
+			d.Checksum = pkg.Sdist.Hash
+		} else if len(pkg.Wheels) == 1 {
+			d.Checksum = pkg.Wheels[0].Hash
+		}
+		dependencies = append(dependencies, d)
+		for _, d := range pkg.Dependencies {
+			m.edges[pkg.Name] = append(m.edges[pkg.Name], normalizePythonName(d.Name))
+		}
+		for _, group := range pkg.OptionalDependencies {
+			for _, d := range group {
+				m.edges[pkg.Name] = append(m.edges[pkg.Name], normalizePythonName(d.Name))
+			}
+		}
+		for _, group := range pkg.DevDependencies {
+			for _, d := range group {
+				m.edges[pkg.Name] = append(m.edges[pkg.Name], normalizePythonName(d.Name))
+			}
+		}
 	}
 	return dependencies, nil
 }
 
-// parsePoetryLock parses a poetry.lock TOML file and extracts dependencies
-func parsePoetryLock(r io.Reader, toolName string) ([]Dependency, error) {
+// parsePoetryLock parses a poetry.lock TOML file and extracts dependencies, and the graph edges between them
+func (m *pythonManager) parsePoetryLock(r io.Reader, toolName string) ([]Dependency, error) {
 	type PoetryPackage struct {
-		Name    string `toml:"name"`
-		Version string `toml:"version"`
+		Name         string         `toml:"name"`
+		Version      string         `toml:"version"`
+		Dependencies map[string]any `toml:"dependencies"`
 	}
 	type PoetryLock struct {
 		Packages []PoetryPackage `toml:"package"`
@@ -483,6 +597,7 @@ func parsePoetryLock(r io.Reader, toolName string) ([]Dependency, error) {
 		return nil, err
 	}
 	var dependencies []Dependency
+	m.edges = make(map[string][]string)
 	for _, pkg := range lock.Packages {
 		dependencies = append(dependencies, Dependency{
 			Name:     pkg.Name,
@@ -490,6 +605,17 @@ func parsePoetryLock(r io.Reader, toolName string) ([]Dependency, error) {
 			IsDirect: false, // Lock files contain both direct and indirect deps
 			ToolName: toolName,
 		})
+		for name := range pkg.Dependencies {
+			m.edges[pkg.Name] = append(m.edges[pkg.Name], normalizePythonName(name))
+		}
 	}
 	return dependencies, nil
 }
+
+var pythonNameSeparatorPatt = regexp.MustCompile(`[-_.]+`)
+
+// normalizePythonName normalizes a package name as in lock files, e.g. "Typing_Extensions" to "typing-extensions".
+// See: https://packaging.python.org/en/latest/specifications/name-normalization/
+func normalizePythonName(name string) string {
+	return pythonNameSeparatorPatt.ReplaceAllString(strings.ToLower(name), "-")
+}
@@ -14,6 +14,7 @@ func TestParsePyprojectTOML(t *testing.T) {
 	fsys := fstest.MapFS{
 		"pyproject.toml": {Data: []byte(`
 			[project]
+			license = { text = "BSD-3-Clause" }
 			dependencies = ["requests>=2.25.1", "numpy==1.21.0"]
 
 			[tool.poetry.dependencies]
@@ -38,4 +39,6 @@ func TestParsePyprojectTOML(t *testing.T) {
 	for _, c := range cases {
 		assert.Equal(t, c.dependencies, m.Find(c.pattern))
 	}
+
+	assert.Equal(t, "BSD-3-Clause", m.(dep.LicenseManager).License())
 }
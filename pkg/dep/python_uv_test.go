@@ -28,24 +28,37 @@ func TestParsePythonUv(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, mgr.Init())
 
+	const pypi = "https://pypi.org/simple"
+	pandas := dep.Dependency{
+		Name: "pandas", Constraint: ">=2.2.0", Version: "2.3.0", IsDirect: true, ToolName: "uv", Registry: pypi,
+		Checksum: "sha256:34600ab34ebf1131a7613a260a61dbe8b62c188ec0ea4c296da7c9a06b004133",
+	}
+	numpy := dep.Dependency{
+		Name: "numpy", Constraint: "==1.26.0", Version: "1.26.0", IsDirect: true, ToolName: "uv", Registry: pypi,
+		Checksum: "sha256:f93fc78fe8bf15afe2b8d6b6499f1c73953169fad1e9a8dd086cdff3190e7fdf",
+	}
+	dateutil := dep.Dependency{
+		Name:       "python-dateutil",
+		Constraint: ">=2.8.0,<3.0.0",
+		Version:    "2.9.0.post0",
+		IsDirect:   true,
+		ToolName:   "uv",
+		Registry:   pypi,
+		Checksum:   "sha256:37dd54208da7e1cd875388217d5e00ebd4179249f90fb72437e91a35459a0ad3",
+	}
+	six := dep.Dependency{
+		Name: "six", Constraint: ">=1.15.0", Version: "1.17.0", IsDirect: true, ToolName: "uv", Registry: pypi,
+		Checksum: "sha256:ff70335d468e7eb6ec65b95b99d3a2836546063f63acc5171de367e834932a81",
+	}
+
 	cases := []struct {
 		pattern      string
 		dependencies []dep.Dependency
 	}{
-		{"pandas", []dep.Dependency{{
-			Name: "pandas", Constraint: ">=2.2.0", Version: "2.3.0", IsDirect: true, ToolName: "uv",
-		}}},
-		{"numpy", []dep.Dependency{{
-			Name: "numpy", Constraint: "==1.26.0", Version: "1.26.0", IsDirect: true, ToolName: "uv",
-		}}},
-		{"python-dateutil", []dep.Dependency{{
-			Name:       "python-dateutil",
-			Constraint: ">=2.8.0,<3.0.0",
-			Version:    "2.9.0.post0",
-			IsDirect:   true,
-			ToolName:   "uv",
-		}}},
-		{"six", []dep.Dependency{{Name: "six", Constraint: ">=1.15.0", Version: "1.17.0", IsDirect: true, ToolName: "uv"}}},
+		{"pandas", []dep.Dependency{pandas}},
+		{"numpy", []dep.Dependency{numpy}},
+		{"python-dateutil", []dep.Dependency{dateutil}},
+		{"six", []dep.Dependency{six}},
 	}
 	for _, c := range cases {
 		assert.Equal(t, c.dependencies, mgr.Find(c.pattern), c.pattern)
@@ -56,20 +69,10 @@ func TestParsePythonUv(t *testing.T) {
 		dependency dep.Dependency
 		found      bool
 	}{
-		{"pandas", dep.Dependency{
-			Name: "pandas", Constraint: ">=2.2.0", Version: "2.3.0", IsDirect: true, ToolName: "uv",
-		}, true},
-		{"numpy", dep.Dependency{
-			Name: "numpy", Constraint: "==1.26.0", Version: "1.26.0", IsDirect: true, ToolName: "uv",
-		}, true},
-		{"python-dateutil", dep.Dependency{
-			Name:       "python-dateutil",
-			Constraint: ">=2.8.0,<3.0.0",
-			Version:    "2.9.0.post0",
-			IsDirect:   true,
-			ToolName:   "uv",
-		}, true},
-		{"six", dep.Dependency{Name: "six", Constraint: ">=1.15.0", Version: "1.17.0", IsDirect: true, ToolName: "uv"}, true},
+		{"pandas", pandas, true},
+		{"numpy", numpy, true},
+		{"python-dateutil", dateutil, true},
+		{"six", six, true},
 		{"notfound", dep.Dependency{}, false},
 	}
 	for _, c := range toGet {
@@ -78,3 +81,48 @@ func TestParsePythonUv(t *testing.T) {
 		assert.Equal(t, c.dependency, d, c.name)
 	}
 }
+
+func TestParsePythonUvSources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"pyproject.toml": {Data: []byte(`[project]
+name = "app"
+dependencies = ["fork", "shared"]
+`)},
+		"uv.lock": {Data: []byte(`version = 1
+
+[[package]]
+name = "app"
+version = "0.1.0"
+source = { editable = "." }
+
+[[package]]
+name = "fork"
+version = "1.0.0"
+source = { git = "https://github.com/acme/fork?rev=main#0a1b2c3d" }
+
+[[package]]
+name = "shared"
+version = "0.2.0"
+source = { directory = "../shared" }
+
+[[package]]
+name = "internal"
+version = "2.0.0"
+source = { registry = "https://pypi.acme.example/simple" }
+wheels = [{ url = "https://pypi.acme.example/internal-2.0.0-py3-none-any.whl", hash = "sha256:4a5f" }]
+`)},
+	}
+
+	mgr, err := dep.GetManager(dep.ManagerTypePython, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, mgr.Init())
+
+	d, _ := mgr.Get("fork")
+	assert.Equal(t, "git+https://github.com/acme/fork?rev=main#0a1b2c3d", d.Source)
+	d, _ = mgr.Get("shared")
+	assert.Equal(t, "path+../shared", d.Source)
+	d, _ = mgr.Get("internal")
+	assert.Equal(t, "https://pypi.acme.example/simple", d.Registry)
+	assert.Equal(t, "sha256:4a5f", d.Checksum)
+	assert.Empty(t, d.Source)
+}
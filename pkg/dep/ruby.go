@@ -18,8 +18,9 @@ type rubyManager struct {
 	path string
 
 	initOnce sync.Once
-	required map[string]string
-	resolved map[string]string
+	deps     map[string]Dependency
+	ruby     Dependency
+	diagnostics
 }
 
 func newRubyManager(fsys fs.FS, path string) Manager {
@@ -38,15 +39,44 @@ func (m *rubyManager) Init() error {
 }
 
 func (m *rubyManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
 	gemfile, err := m.fsys.Open(filepath.Join(m.path, "Gemfile"))
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
-		}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return err
 	}
-	defer gemfile.Close()
-	m.required = parseGemfile(gemfile)
+	gemspecPaths := []string{"."}
+	if err == nil {
+		defer gemfile.Close()
+		parsed, err := parseGemfile(gemfile)
+		if err != nil {
+			return err
+		}
+		m.ruby = parsed.ruby
+		gemspecPaths = append(gemspecPaths, parsed.gemspecPaths...)
+		for _, d := range parsed.deps {
+			m.deps[d.Name] = d
+		}
+	}
+
+	// Read gemspec files. Dependencies declared in the Gemfile take precedence.
+	for _, p := range gemspecPaths {
+		matches, err := fs.Glob(m.fsys, filepath.Join(m.path, p, "*.gemspec"))
+		if err != nil {
+			return err
+		}
+		for _, match := range matches {
+			deps, err := m.parseGemspecFile(match)
+			if err != nil {
+				return err
+			}
+			for _, d := range deps {
+				if _, ok := m.deps[d.Name]; !ok {
+					m.deps[d.Name] = d
+				}
+			}
+		}
+	}
 
 	gemfileLock, err := m.fsys.Open(filepath.Join(m.path, "Gemfile.lock"))
 	if err != nil {
@@ -56,91 +86,364 @@ func (m *rubyManager) parse() error {
 		return err
 	}
 	defer gemfileLock.Close()
-	m.resolved = parseGemfileLock(gemfileLock)
+	locked, err := parseGemfileLock(gemfileLock)
+	if err != nil {
+		return err
+	}
+	if locked.rubyVersion != "" {
+		m.ruby.Name = "ruby"
+		m.ruby.Version = locked.rubyVersion
+		m.ruby.ToolName = "bundler"
+	}
+	for name, spec := range locked.specs {
+		if d, ok := m.deps[name]; ok {
+			// Update existing dependency (preserve IsDirect status)
+			d.Version = spec.Version
+			if d.Source == "" {
+				d.Source = spec.Source
+			}
+			m.deps[name] = d
+		} else {
+			// New dependency only in the lock file (indirect)
+			spec.ToolName = "bundler"
+			m.deps[name] = spec
+		}
+	}
 	return nil
 }
 
-func (m *rubyManager) Get(name string) (Dependency, bool) {
-	constraint, hasConstraint := m.required[name]
-	version, hasVersion := m.resolved[name]
-	if !hasConstraint && !hasVersion {
-		return Dependency{}, false
+func (m *rubyManager) parseGemspecFile(filename string) ([]Dependency, error) {
+	f, err := m.fsys.Open(filename)
+	if err != nil {
+		return nil, err
 	}
-	return Dependency{
-		Name:       name,
-		Constraint: constraint,
-		Version:    version,
-		IsDirect:   hasConstraint, // Direct if it has a constraint from Gemfile
-		ToolName:   "bundler",
-	}, true
+	defer f.Close()
+	return parseGemspec(f)
+}
+
+func (m *rubyManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
 }
 
 func (m *rubyManager) Find(pattern string) []Dependency {
-	seen := make(map[string]struct{})
 	var deps []Dependency
-	// First, add all with constraints (direct dependencies)
-	for name, constraint := range m.required {
+	for name, dep := range m.deps {
 		if wildcard.Match(pattern, name) {
-			deps = append(deps, Dependency{
-				Name:       name,
-				Constraint: constraint,
-				Version:    m.resolved[name],
-				IsDirect:   true,
-				ToolName:   "bundler",
-			})
-			seen[name] = struct{}{}
-		}
-	}
-	// Then, add any resolved-only deps not already included (indirect dependencies)
-	for name, version := range m.resolved {
-		if _, already := seen[name]; !already && wildcard.Match(pattern, name) {
-			deps = append(deps, Dependency{
-				Name:     name,
-				Version:  version,
-				IsDirect: false,
-				ToolName: "bundler",
-			})
+			deps = append(deps, dep)
 		}
 	}
 	return deps
 }
 
-var gemPatt = regexp.MustCompile(`^gem\s+['"]([a-zA-Z0-9_-]+)['"](,\s*['"]([^'"]+)['"])?`)
+// Platform returns the Ruby version requirement from the Gemfile and Gemfile.lock, if any.
+func (m *rubyManager) Platform() []Dependency {
+	if m.ruby.Name == "" {
+		return nil
+	}
+	return []Dependency{m.ruby}
+}
+
+var (
+	gemPatt        = regexp.MustCompile(`^gem\s*\(?\s*['"]([\w.-]+)['"]\s*,?\s*(.*?)\)?$`)
+	gemRubyPatt    = regexp.MustCompile(`^ruby\s*\(?\s*['"]([^'"]+)['"]`)
+	gemspecPatt    = regexp.MustCompile(`^gemspec\b(.*)$`)
+	gemBlockPatt   = regexp.MustCompile(`^(\w+)\b\s*\(?(.*?)\)?\s+do(?:\s*\|[^|]*\|)?$`)
+	gemOptionPatt  = regexp.MustCompile(`^:?(\w+)(?::|\s*=>)\s*(.+)$`)
+	gemSymbolsPatt = regexp.MustCompile(`\w+`)
+	gemQuotedPatt  = regexp.MustCompile(`['"]([^'"]*)['"]`)
+	gemspecDepPatt = regexp.MustCompile(`\.add_(runtime_|development_)?dependency\s*\(?\s*['"]([\w.-]+)['"]\s*,?(.*)$`)
+)
 
-func parseGemfile(r io.Reader) map[string]string {
-	deps := make(map[string]string)
+// gemfileBlock is a "do ... end" block in a Gemfile, which may set groups or a source for the gems inside it.
+type gemfileBlock struct {
+	groups []string
+	source string
+}
+
+type parsedGemfile struct {
+	deps         []Dependency
+	ruby         Dependency
+	gemspecPaths []string
+}
+
+// parseGemfile parses a Gemfile, including group blocks, git and path sources, gemspec directives and the Ruby version.
+func parseGemfile(r io.Reader) (*parsedGemfile, error) {
+	var parsed parsedGemfile
+	var blocks []gemfileBlock
 	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "gem ") {
-			matches := gemPatt.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				name := matches[1]
-				version := ""
-				if len(matches) > 3 {
-					version = matches[3]
+		if i := strings.Index(line, " #"); i != -1 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		if matches := gemBlockPatt.FindStringSubmatch(line); matches != nil {
+			var block gemfileBlock
+			switch matches[1] {
+			case "group":
+				block.groups = gemSymbolsPatt.FindAllString(matches[2], -1)
+			case "git", "github", "path":
+				if q := gemQuotedPatt.FindStringSubmatch(matches[2]); q != nil {
+					block.source = gemSource(matches[1], q[1])
 				}
-				deps[name] = version
+			}
+			blocks = append(blocks, block)
+			continue
+		}
+		if startsRubyBlock(line) {
+			blocks = append(blocks, gemfileBlock{})
+			continue
+		}
+
+		if matches := gemRubyPatt.FindStringSubmatch(line); matches != nil {
+			parsed.ruby = Dependency{Name: "ruby", Constraint: matches[1], IsDirect: true, ToolName: "bundler"}
+			continue
+		}
+
+		if matches := gemspecPatt.FindStringSubmatch(line); matches != nil {
+			_, options := parseGemArgs(matches[1])
+			if p, ok := options["path"]; ok {
+				parsed.gemspecPaths = append(parsed.gemspecPaths, unquoteRuby(p))
+			}
+			continue
+		}
+
+		matches := gemPatt.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		constraints, options := parseGemArgs(matches[2])
+		var groups []string
+		var source string
+		for _, b := range blocks {
+			groups = append(groups, b.groups...)
+			if b.source != "" {
+				source = b.source
+			}
+		}
+		for _, k := range []string{"group", "groups"} {
+			if v, ok := options[k]; ok {
+				groups = append(groups, gemSymbolsPatt.FindAllString(strings.TrimPrefix(v, "%i"), -1)...)
 			}
 		}
+		for _, k := range []string{"git", "github", "path"} {
+			if v, ok := options[k]; ok {
+				source = gemSource(k, unquoteRuby(v))
+			}
+		}
+		parsed.deps = append(parsed.deps, Dependency{
+			Name:       matches[1],
+			Constraint: strings.Join(constraints, ", "),
+			IsDirect:   true,
+			IsDevOnly:  isDevOnlyGemGroups(groups),
+			ToolName:   "bundler",
+			Source:     source,
+		})
 	}
 
-	return deps
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// startsRubyBlock checks if a line starts a block that will be closed by "end", apart from "do" blocks.
+func startsRubyBlock(line string) bool {
+	for _, keyword := range []string{"if", "unless", "case", "begin", "while", "until", "def"} {
+		if line == keyword || strings.HasPrefix(line, keyword+" ") {
+			return true
+		}
+	}
+	return false
 }
 
-var bundlerDepPatt = regexp.MustCompile(`^\s{4}([a-zA-Z0-9_-]+) \(([^)]+)\)$`)
+// parseGemArgs parses the arguments after a gem name into version constraints and options.
+func parseGemArgs(args string) (constraints []string, options map[string]string) {
+	options = make(map[string]string)
+	for _, arg := range splitRubyArgs(args) {
+		if q := gemQuotedPatt.FindStringSubmatch(arg); q != nil && q[0] == arg {
+			constraints = append(constraints, q[1])
+		} else if matches := gemOptionPatt.FindStringSubmatch(arg); matches != nil {
+			options[matches[1]] = strings.TrimSpace(matches[2])
+		}
+	}
+	return constraints, options
+}
 
-func parseGemfileLock(r io.Reader) map[string]string {
-	deps := make(map[string]string)
+// splitRubyArgs splits a list of arguments by commas, ignoring commas in quotes or brackets.
+func splitRubyArgs(s string) []string {
+	var (
+		args  []string
+		quote rune
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		args = append(args, rest)
+	}
+	return args
+}
+
+func unquoteRuby(s string) string {
+	if q := gemQuotedPatt.FindStringSubmatch(s); q != nil {
+		return q[1]
+	}
+	return s
+}
+
+// gemSource formats a git or path source.
+func gemSource(kind, location string) string {
+	switch kind {
+	case "github":
+		return "git+https://github.com/" + location + ".git"
+	case "git":
+		return "git+" + location
+	case "path":
+		return "path+" + location
+	}
+	return ""
+}
+
+// isDevOnlyGemGroups checks if a list of Bundler groups contains only development or test groups.
+func isDevOnlyGemGroups(groups []string) bool {
+	if len(groups) == 0 {
+		return false
+	}
+	for _, g := range groups {
+		if g != "development" && g != "test" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseGemspec parses runtime and development dependencies from a .gemspec file.
+func parseGemspec(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		matches := gemspecDepPatt.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matches == nil {
+			continue
+		}
+		var constraints []string
+		for _, q := range gemQuotedPatt.FindAllStringSubmatch(matches[3], -1) {
+			constraints = append(constraints, q[1])
+		}
+		deps = append(deps, Dependency{
+			Name:       matches[2],
+			Constraint: strings.Join(constraints, ", "),
+			IsDirect:   true,
+			IsDevOnly:  matches[1] == "development_",
+			ToolName:   "bundler",
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+var (
+	bundlerDepPatt    = regexp.MustCompile(`^\s{4}([\w.-]+) \(([^)]+)\)$`)
+	bundlerRemotePatt = regexp.MustCompile(`^\s{2}(remote|revision): (.+)$`)
+	bundlerRubyPatt   = regexp.MustCompile(`^\s+ruby (\S+)$`)
+	// The patch level, e.g. "p100" in "3.2.2p100".
+	bundlerPatchLevelPatt = regexp.MustCompile(`p\d+$`)
+)
+
+type parsedGemfileLock struct {
+	specs       map[string]Dependency
+	rubyVersion string
+}
+
+// parseGemfileLock reads resolved gems from the specs in the GEM, GIT and PATH sections of a Gemfile.lock.
+func parseGemfileLock(r io.Reader) (*parsedGemfileLock, error) {
+	parsed := &parsedGemfileLock{specs: make(map[string]Dependency)}
+	var section, remote, revision string
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
+		if line != "" && line[0] != ' ' {
+			section = strings.TrimSpace(line)
+			remote, revision = "", ""
+			continue
+		}
+		switch section {
+		case "RUBY VERSION":
+			if matches := bundlerRubyPatt.FindStringSubmatch(line); matches != nil {
+				parsed.rubyVersion = bundlerPatchLevelPatt.ReplaceAllString(matches[1], "")
+			}
+			continue
+		case "", "GEM", "GIT", "PATH":
+		default:
+			continue
+		}
+		if matches := bundlerRemotePatt.FindStringSubmatch(line); matches != nil {
+			if matches[1] == "remote" {
+				remote = matches[2]
+			} else {
+				revision = matches[2]
+			}
+			continue
+		}
 		matches := bundlerDepPatt.FindStringSubmatch(line)
-		if len(matches) > 2 {
-			deps[matches[1]] = matches[2]
+		if matches == nil {
+			continue
+		}
+		var source string
+		switch section {
+		case "GIT":
+			source = "git+" + remote
+			if revision != "" {
+				source += "#" + revision
+			}
+		case "PATH":
+			source = "path+" + remote
+		}
+		// A gem may be locked for several platforms, with the platform after the version, e.g.
+		// "nokogiri (1.15.4-x86_64-linux)". Bundler splits them at the first hyphen, as versions do not contain any.
+		version, _, _ := strings.Cut(matches[2], "-")
+		if _, ok := parsed.specs[matches[1]]; ok {
+			continue
+		}
+		parsed.specs[matches[1]] = Dependency{
+			Name:    matches[1],
+			Version: version,
+			Source:  source,
 		}
 	}
-
-	return deps
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
 }
@@ -20,7 +20,8 @@ gem 'nokogiri', '>= 1.10', '< 2.0'`),
 		"Gemfile.lock": {
 			Data: []byte(`    rails (6.1.4.1)
     puma (5.3.2)
-    nokogiri (1.11.3)`),
+    nokogiri (1.11.3-arm64-darwin)
+    nokogiri (1.11.3-x86_64-linux)`),
 		},
 	}
 
@@ -60,6 +61,13 @@ gem 'nokogiri', '>= 1.10', '< 2.0'`),
 			IsDirect:   true,
 			ToolName:   "bundler",
 		}, found: true},
+		{name: "nokogiri", dependency: dep.Dependency{
+			Name:       "nokogiri",
+			Version:    "1.11.3",
+			Constraint: ">= 1.10, < 2.0",
+			IsDirect:   true,
+			ToolName:   "bundler",
+		}, found: true},
 	}
 	for _, c := range toGet {
 		d, ok := m.Get(c.name)
@@ -67,3 +75,137 @@ gem 'nokogiri', '>= 1.10', '< 2.0'`),
 		assert.Equal(t, c.dependency, d)
 	}
 }
+
+func TestRubyGroupsAndSources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Gemfile": {Data: []byte(`source "https://rubygems.org"
+
+ruby "~> 3.2"
+
+gemspec
+
+gem "rails", "~> 7.1.0"
+gem "pg", ">= 1.1", "< 2.0"
+gem "my_engine", path: "engines/my_engine"
+gem "kaminari", github: "kaminari/kaminari", branch: "master"
+gem "bootsnap", require: false
+
+group :development, :test do
+  gem "debug", platforms: %i[ mri windows ]
+  gem "rspec-rails"
+end
+
+group :production do
+  gem "lograge"
+end
+
+gem "rubocop", group: :development, require: false
+`)},
+		"my_gem.gemspec": {Data: []byte(`Gem::Specification.new do |spec|
+  spec.name = "my_gem"
+  spec.add_dependency "rack", ">= 2.2", "< 4"
+  spec.add_runtime_dependency("zeitwerk", "~> 2.6")
+  spec.add_development_dependency "rake", "~> 13.0"
+end
+`)},
+		"Gemfile.lock": {Data: []byte(`PATH
+  remote: engines/my_engine
+  specs:
+    my_engine (0.1.0)
+
+GIT
+  remote: https://github.com/kaminari/kaminari.git
+  revision: 2b7b4ea4ff07a1d2a2aa57b0a6f81dea28ad72b3
+  branch: master
+  specs:
+    kaminari (1.2.2)
+      kaminari-core (= 1.2.2)
+
+GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.1.3)
+      rack (>= 2.2.4)
+    debug (1.9.1)
+    pg (1.5.4)
+    rack (3.0.9)
+    rails (7.1.3)
+      actionpack (= 7.1.3)
+
+PLATFORMS
+  ruby
+
+DEPENDENCIES
+  debug
+  pg (>= 1.1, < 2.0)
+  rails (~> 7.1.0)
+
+RUBY VERSION
+   ruby 3.2.2p53
+
+BUNDLED WITH
+   2.5.3
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeRuby, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	toGet := []struct {
+		name       string
+		dependency dep.Dependency
+		found      bool
+	}{
+		{name: "pg", dependency: dep.Dependency{
+			Name: "pg", Constraint: ">= 1.1, < 2.0", Version: "1.5.4", IsDirect: true, ToolName: "bundler",
+		}, found: true},
+		{name: "my_engine", dependency: dep.Dependency{
+			Name: "my_engine", Version: "0.1.0", IsDirect: true, ToolName: "bundler",
+			Source: "path+engines/my_engine",
+		}, found: true},
+		{name: "kaminari", dependency: dep.Dependency{
+			Name: "kaminari", Version: "1.2.2", IsDirect: true, ToolName: "bundler",
+			Source: "git+https://github.com/kaminari/kaminari.git",
+		}, found: true},
+		{name: "debug", dependency: dep.Dependency{
+			Name: "debug", Version: "1.9.1", IsDirect: true, IsDevOnly: true, ToolName: "bundler",
+		}, found: true},
+		{name: "rspec-rails", dependency: dep.Dependency{
+			Name: "rspec-rails", IsDirect: true, IsDevOnly: true, ToolName: "bundler",
+		}, found: true},
+		{name: "lograge", dependency: dep.Dependency{
+			Name: "lograge", IsDirect: true, ToolName: "bundler",
+		}, found: true},
+		{name: "rubocop", dependency: dep.Dependency{
+			Name: "rubocop", IsDirect: true, IsDevOnly: true, ToolName: "bundler",
+		}, found: true},
+		{name: "bootsnap", dependency: dep.Dependency{
+			Name: "bootsnap", IsDirect: true, ToolName: "bundler",
+		}, found: true},
+		{name: "rack", dependency: dep.Dependency{
+			Name: "rack", Constraint: ">= 2.2, < 4", Version: "3.0.9", IsDirect: true, ToolName: "bundler",
+		}, found: true},
+		{name: "zeitwerk", dependency: dep.Dependency{
+			Name: "zeitwerk", Constraint: "~> 2.6", IsDirect: true, ToolName: "bundler",
+		}, found: true},
+		{name: "rake", dependency: dep.Dependency{
+			Name: "rake", Constraint: "~> 13.0", IsDirect: true, IsDevOnly: true, ToolName: "bundler",
+		}, found: true},
+		{name: "actionpack", dependency: dep.Dependency{
+			Name: "actionpack", Version: "7.1.3", ToolName: "bundler",
+		}, found: true},
+		{name: "kaminari-core"},
+	}
+	for _, c := range toGet {
+		d, ok := m.Get(c.name)
+		assert.Equal(t, c.found, ok, c.name)
+		assert.Equal(t, c.dependency, d, c.name)
+	}
+
+	pm, ok := m.(dep.PlatformManager)
+	require.True(t, ok)
+	assert.Equal(t, []dep.Dependency{{
+		Name: "ruby", Constraint: "~> 3.2", Version: "3.2.2", IsDirect: true, ToolName: "bundler",
+	}}, pm.Platform())
+}
@@ -1,11 +1,11 @@
 package dep
 
 import (
-	"encoding/json"
 	"errors"
 	"io"
 	"io/fs"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/toml"
@@ -18,6 +18,9 @@ type rustManager struct {
 
 	initOnce sync.Once
 	deps     map[string]Dependency
+	edges    map[string][]string
+	license  string
+	diagnostics
 }
 
 func newRustManager(fsys fs.FS, path string) Manager {
@@ -36,40 +39,58 @@ func (m *rustManager) Init() error {
 }
 
 func (m *rustManager) parse() error {
-	manifestFile, err := m.fsys.Open(filepath.Join(m.path, "Cargo.toml"))
-	if err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
-		}
+	manifest, err := m.parseManifest(m.path)
+	if err != nil || manifest == nil {
+		return m.report(err)
 	}
-	defer manifestFile.Close()
-	regularDeps, devDeps, err := parseCargoTOML(manifestFile)
-	if err != nil {
+
+	// Find the workspace root, which may be the same directory, for inherited dependencies and the shared lock file.
+	root, rootPath, err := m.findWorkspaceRoot(manifest)
+	if m.report(err) != nil {
 		return err
 	}
 
+	m.license = manifest.license
+	if manifest.inheritsLicense && root != nil {
+		m.license = root.workspaceLicense
+	}
+
 	m.deps = make(map[string]Dependency)
-	// Direct regular dependencies from Cargo.toml
-	for name, constraint := range regularDeps {
-		m.deps[name] = Dependency{
-			Name:       name,
-			Constraint: constraint,
-			IsDirect:   true,
-			ToolName:   "cargo",
+	for _, d := range manifest.deps {
+		spec := d.spec
+		if spec.Workspace {
+			if root == nil {
+				continue
+			}
+			inherited, ok := root.workspaceDeps[d.key]
+			if !ok {
+				continue
+			}
+			spec = inherited
+		}
+		name := d.key
+		if spec.Package != "" {
+			name = spec.Package // The dependency is renamed
+		}
+		if existing, ok := m.deps[name]; ok && !existing.IsDevOnly {
+			continue // Regular dependencies take precedence over dev dependencies
 		}
-	}
-	// Direct dev dependencies from Cargo.toml
-	for name, constraint := range devDeps {
 		m.deps[name] = Dependency{
 			Name:       name,
-			Constraint: constraint,
+			Constraint: spec.Version,
 			IsDirect:   true,
-			IsDevOnly:  true,
+			IsDevOnly:  d.devOnly,
 			ToolName:   "cargo",
+			Source:     spec.source(),
 		}
 	}
 
-	lockFile, err := m.fsys.Open(filepath.Join(m.path, "Cargo.lock"))
+	// The lock file is shared by all workspace members, in the workspace root.
+	lockPath := m.path
+	if _, err := fs.Stat(m.fsys, filepath.Join(m.path, "Cargo.lock")); err != nil && root != nil {
+		lockPath = rootPath
+	}
+	lockFile, err := m.fsys.Open(filepath.Join(lockPath, "Cargo.lock"))
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil
@@ -77,29 +98,69 @@ func (m *rustManager) parse() error {
 		return err
 	}
 	defer lockFile.Close()
-	versions, err := parseCargoLock(lockFile)
+	locked, edges, err := parseCargoLock(lockFile)
 	if err != nil {
-		return err
+		return m.report(newDiagnostic(filepath.Join(lockPath, "Cargo.lock"), nil, err))
 	}
-	for name, version := range versions {
+	m.edges = edges
+	for name, l := range locked {
 		if d, ok := m.deps[name]; ok {
 			// Update existing dependency (preserve IsDirect status)
-			d.Version = version
+			d.Version = l.Version
+			d.Registry = l.Registry
+			d.Checksum = l.Checksum
+			if l.Source != "" && (d.Source == "" || strings.HasPrefix(d.Source, "git+")) {
+				d.Source = l.Source // Includes the locked revision
+			}
 			m.deps[name] = d
-		} else {
+		} else if lockPath == m.path {
 			// New dependency only in lock file (indirect)
-			m.deps[name] = Dependency{
-				Name:     name,
-				Version:  version,
-				IsDirect: false,
-				ToolName: "cargo",
-			}
+			// A workspace member does not list these: they belong to the workspace root.
+			l.Name = name
+			l.IsDirect = false
+			l.ToolName = "cargo"
+			m.deps[name] = l
 		}
 	}
 
 	return nil
 }
 
+func (m *rustManager) parseManifest(path string) (*cargoManifest, error) {
+	manifestFile, err := m.fsys.Open(filepath.Join(path, "Cargo.toml"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer manifestFile.Close()
+	manifest, err := parseCargoTOML(manifestFile)
+	if err != nil {
+		return nil, newDiagnostic(filepath.Join(path, "Cargo.toml"), nil, err)
+	}
+	return manifest, nil
+}
+
+// findWorkspaceRoot finds the manifest containing a [workspace] section, in the path or its parent directories.
+func (m *rustManager) findWorkspaceRoot(manifest *cargoManifest) (*cargoManifest, string, error) {
+	if manifest.isWorkspace {
+		return manifest, m.path, nil
+	}
+	dir := m.path
+	for dir != "." && dir != "/" && dir != "" {
+		dir = filepath.Dir(dir)
+		parent, err := m.parseManifest(dir)
+		if err != nil {
+			return nil, "", err
+		}
+		if parent != nil && parent.isWorkspace {
+			return parent, dir, nil
+		}
+	}
+	return nil, "", nil
+}
+
 func (m *rustManager) Find(pattern string) []Dependency {
 	var deps []Dependency
 	for name, dep := range m.deps {
@@ -115,72 +176,169 @@ func (m *rustManager) Get(name string) (Dependency, bool) {
 	return dep, ok
 }
 
+// License returns the license declared in Cargo.toml, which may be inherited from the workspace.
+func (m *rustManager) License() string {
+	return m.license
+}
+
+// Graph returns the dependency graph from Cargo.lock. In a workspace member, it only includes the packages listed by
+// the member itself.
+func (m *rustManager) Graph() *Graph {
+	if m.edges == nil {
+		return nil
+	}
+	return newGraph(m.deps, m.edges)
+}
+
+type cargoDependencyTables struct {
+	Dependencies      map[string]toml.Primitive `toml:"dependencies"`
+	DevDependencies   map[string]toml.Primitive `toml:"dev-dependencies"`
+	BuildDependencies map[string]toml.Primitive `toml:"build-dependencies"`
+}
+
 type cargoTOML struct {
-	Dependencies    map[string]toml.Primitive `toml:"dependencies"`
-	DevDependencies map[string]toml.Primitive `toml:"dev-dependencies"`
+	cargoDependencyTables
+	Package struct {
+		License toml.Primitive `toml:"license"` // A string, or a table to inherit it from the workspace
+	} `toml:"package"`
+	Target    map[string]cargoDependencyTables `toml:"target"`
+	Workspace *struct {
+		Dependencies map[string]toml.Primitive `toml:"dependencies"`
+		Package      struct {
+			License string `toml:"license"`
+		} `toml:"package"`
+	} `toml:"workspace"`
+}
+
+// cargoDependencySpec is a dependency specification, which may be a simple version string or a table.
+// See: https://doc.rust-lang.org/cargo/reference/specifying-dependencies.html
+type cargoDependencySpec struct {
+	Version   string `toml:"version"`
+	Package   string `toml:"package"`
+	Workspace bool   `toml:"workspace"`
+	Git       string `toml:"git"`
+	Path      string `toml:"path"`
+}
+
+func (s cargoDependencySpec) source() string {
+	switch {
+	case s.Git != "":
+		return "git+" + s.Git
+	case s.Path != "":
+		return "path+" + s.Path
+	}
+	return ""
+}
+
+type cargoManifestDependency struct {
+	key     string // The key in the manifest, which may differ from the package name
+	spec    cargoDependencySpec
+	devOnly bool
+}
+
+type cargoManifest struct {
+	deps             []cargoManifestDependency
+	license          string
+	inheritsLicense  bool
+	isWorkspace      bool
+	workspaceDeps    map[string]cargoDependencySpec
+	workspaceLicense string
 }
 
 type cargoLock struct {
 	Packages []struct {
-		Name    string `toml:"name"`
-		Version string `toml:"version"`
+		Name         string   `toml:"name"`
+		Version      string   `toml:"version"`
+		Source       string   `toml:"source"` // e.g. "registry+https://github.com/rust-lang/crates.io-index"
+		Checksum     string   `toml:"checksum"`
+		Dependencies []string `toml:"dependencies"`
 	} `toml:"package"`
 }
 
-// parseCargoTOML parses the Cargo.toml manifest (dependency names with version constraints).
-func parseCargoTOML(r io.Reader) (regular map[string]string, dev map[string]string, err error) {
+// parseCargoTOML parses the Cargo.toml manifest, including regular, dev, build and platform-specific dependencies,
+// and the [workspace.dependencies] that members can inherit.
+func parseCargoTOML(r io.Reader) (*cargoManifest, error) {
 	var ct cargoTOML
 	md, err := toml.NewDecoder(r).Decode(&ct)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	regular = make(map[string]string)
-	dev = make(map[string]string)
-
-	// Parse regular dependencies
-	for name, spec := range ct.Dependencies {
-		var asStr string
-		if err := md.PrimitiveDecode(spec, &asStr); err == nil {
-			regular[name] = asStr
-			continue
+	decode := func(spec toml.Primitive) cargoDependencySpec {
+		var s cargoDependencySpec
+		if err := md.PrimitiveDecode(spec, &s.Version); err != nil {
+			_ = md.PrimitiveDecode(spec, &s) // Invalid entries are skipped
 		}
-		var asMap map[string]string
-		if err := md.PrimitiveDecode(spec, &asMap); err == nil {
-			b, _ := json.Marshal(asMap)
-			regular[name] = string(b)
+		return s
+	}
+
+	manifest := &cargoManifest{}
+	if err := md.PrimitiveDecode(ct.Package.License, &manifest.license); err != nil {
+		var inherited struct {
+			Workspace bool `toml:"workspace"`
 		}
+		_ = md.PrimitiveDecode(ct.Package.License, &inherited) // An invalid license is ignored
+		manifest.inheritsLicense = inherited.Workspace
 	}
 
-	// Parse dev dependencies
-	for name, spec := range ct.DevDependencies {
-		var asStr string
-		if err := md.PrimitiveDecode(spec, &asStr); err == nil {
-			dev[name] = asStr
-			continue
+	addTables := func(t cargoDependencyTables) {
+		for key, spec := range t.Dependencies {
+			manifest.deps = append(manifest.deps, cargoManifestDependency{key: key, spec: decode(spec)})
+		}
+		for key, spec := range t.BuildDependencies {
+			manifest.deps = append(manifest.deps, cargoManifestDependency{key: key, spec: decode(spec)})
 		}
-		var asMap map[string]string
-		if err := md.PrimitiveDecode(spec, &asMap); err == nil {
-			b, _ := json.Marshal(asMap)
-			dev[name] = string(b)
+		for key, spec := range t.DevDependencies {
+			manifest.deps = append(manifest.deps, cargoManifestDependency{key: key, spec: decode(spec), devOnly: true})
 		}
 	}
+	addTables(ct.cargoDependencyTables)
+	for _, t := range ct.Target {
+		addTables(t)
+	}
 
-	return regular, dev, nil
+	if ct.Workspace != nil {
+		manifest.isWorkspace = true
+		manifest.workspaceLicense = ct.Workspace.Package.License
+		manifest.workspaceDeps = make(map[string]cargoDependencySpec, len(ct.Workspace.Dependencies))
+		for key, spec := range ct.Workspace.Dependencies {
+			manifest.workspaceDeps[key] = decode(spec)
+		}
+	}
+
+	return manifest, nil
 }
 
-// parseCargoLock parses the Cargo.lock file (dependency names with resolved versions).
-func parseCargoLock(r io.Reader) (map[string]string, error) {
+// parseCargoLock parses the Cargo.lock file (dependency names with resolved versions, sources and checksums), and
+// the names of the packages required by each package.
+func parseCargoLock(r io.Reader) (locked map[string]Dependency, edges map[string][]string, err error) {
 	var cl cargoLock
-	_, err := toml.NewDecoder(r).Decode(&cl)
-	if err != nil {
-		return nil, err
+	if _, err := toml.NewDecoder(r).Decode(&cl); err != nil {
+		return nil, nil, err
 	}
 
-	dependencies := make(map[string]string)
+	locked = make(map[string]Dependency)
+	edges = make(map[string][]string)
 	for _, pkg := range cl.Packages {
-		dependencies[pkg.Name] = pkg.Version
+		d := Dependency{Version: pkg.Version}
+		// Sources are in the format "registry+<index URL>", "sparse+<index URL>" or "git+<URL>#<revision>".
+		// Local packages have no source.
+		switch kind, url, _ := strings.Cut(pkg.Source, "+"); kind {
+		case "registry", "sparse":
+			d.Registry = url
+		case "git":
+			d.Source = pkg.Source
+		}
+		if pkg.Checksum != "" {
+			d.Checksum = "sha256:" + pkg.Checksum
+		}
+		locked[pkg.Name] = d
+		for _, required := range pkg.Dependencies {
+			// Entries are in the format "name", "name version" or "name version (source)".
+			name, _, _ := strings.Cut(required, " ")
+			edges[pkg.Name] = append(edges[pkg.Name], name)
+		}
 	}
 
-	return dependencies, nil
+	return locked, edges, nil
 }
@@ -30,6 +30,7 @@ func TestParseCargoTOMLAndLock(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, m.Init())
 
+	const cratesIO = "https://github.com/rust-lang/crates.io-index"
 	cases := []struct {
 		pattern      string
 		dependencies []dep.Dependency
@@ -40,17 +41,24 @@ func TestParseCargoTOMLAndLock(t *testing.T) {
 			Version:    "0.5.1",
 			IsDirect:   true,
 			ToolName:   "cargo",
+			Registry:   cratesIO,
+			Checksum:   "sha256:a516907296a31df7dc04310e7043b61d71954d703b603cc6867a026d7e72d73f",
 		}}},
 		{"serde", []dep.Dependency{{
 			Name:     "serde",
 			Version:  "1.0.217",
 			IsDirect: false,
 			ToolName: "cargo",
+			Registry: cratesIO,
+			Checksum: "sha256:02fc4265df13d6fa1d00ecff087228cc0a2b5f3c0e87e258d8b94a156e984c70",
 		}}},
 		{"rand*", []dep.Dependency{
-			{Name: "rand", Version: "0.9.0", Constraint: "0.9.0", IsDirect: true, ToolName: "cargo"},
-			{Name: "rand_chacha", Version: "0.9.0", IsDirect: false, ToolName: "cargo"},
-			{Name: "rand_core", Version: "0.9.0", IsDirect: false, ToolName: "cargo"},
+			{Name: "rand", Version: "0.9.0", Constraint: "0.9.0", IsDirect: true, ToolName: "cargo", Registry: cratesIO,
+				Checksum: "sha256:3779b94aeb87e8bd4e834cee3650289ee9e0d5677f976ecdb6d219e5f4f6cd94"},
+			{Name: "rand_chacha", Version: "0.9.0", IsDirect: false, ToolName: "cargo", Registry: cratesIO,
+				Checksum: "sha256:d3022b5f1df60f26e1ffddd6c66e8aa15de382ae63b3a0c1bfc0e4d3e3f325cb"},
+			{Name: "rand_core", Version: "0.9.0", IsDirect: false, ToolName: "cargo", Registry: cratesIO,
+				Checksum: "sha256:b08f3c9802962f7e1b25113931d94f43ed9725bebc59db9d0c3e9a23b67e15ff"},
 		}},
 	}
 	for _, c := range cases {
@@ -61,3 +69,129 @@ func TestParseCargoTOMLAndLock(t *testing.T) {
 		assert.Equal(t, c.dependencies, deps)
 	}
 }
+
+func TestCargoWorkspace(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Cargo.toml": {Data: []byte(`[workspace]
+members = ["crates/*"]
+
+[workspace.package]
+license = "MIT OR Apache-2.0"
+
+[workspace.dependencies]
+serde = { version = "1.0.197", features = ["derive"] }
+tokio = "1.36"
+`)},
+		"Cargo.lock": {Data: []byte(`version = 3
+
+[[package]]
+name = "serde"
+version = "1.0.197"
+
+[[package]]
+name = "tokio"
+version = "1.36.0"
+
+[[package]]
+name = "openssl"
+version = "0.10.64"
+
+[[package]]
+name = "cc"
+version = "1.0.90"
+
+[[package]]
+name = "winapi"
+version = "0.3.9"
+`)},
+		"crates/app/Cargo.toml": {Data: []byte(`[package]
+name = "app"
+version = "0.1.0"
+license.workspace = true
+
+[dependencies]
+serde = { workspace = true }
+tokio.workspace = true
+ssl = { package = "openssl", version = "0.10" }
+core = { path = "../core" }
+tracing = { git = "https://github.com/tokio-rs/tracing", branch = "main" }
+
+[build-dependencies]
+cc = "1.0"
+
+[target.'cfg(windows)'.dependencies]
+winapi = { version = "0.3", features = ["winuser"] }
+
+[dev-dependencies]
+tokio = { workspace = true, features = ["test-util"] }
+criterion = "0.5"
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeRust, fsys, "crates/app")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	deps := m.Find("*")
+	slices.SortFunc(deps, func(a, b dep.Dependency) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	assert.Equal(t, []dep.Dependency{
+		{Name: "cc", Constraint: "1.0", Version: "1.0.90", IsDirect: true, ToolName: "cargo"},
+		{Name: "core", IsDirect: true, ToolName: "cargo", Source: "path+../core"},
+		{Name: "criterion", Constraint: "0.5", IsDirect: true, IsDevOnly: true, ToolName: "cargo"},
+		{Name: "openssl", Constraint: "0.10", Version: "0.10.64", IsDirect: true, ToolName: "cargo"},
+		{Name: "serde", Constraint: "1.0.197", Version: "1.0.197", IsDirect: true, ToolName: "cargo"},
+		{Name: "tokio", Constraint: "1.36", Version: "1.36.0", IsDirect: true, ToolName: "cargo"},
+		{Name: "tracing", IsDirect: true, ToolName: "cargo", Source: "git+https://github.com/tokio-rs/tracing"},
+		{Name: "winapi", Constraint: "0.3", Version: "0.3.9", IsDirect: true, ToolName: "cargo"},
+	}, deps)
+
+	assert.Equal(t, "MIT OR Apache-2.0", m.(dep.LicenseManager).License())
+}
+
+func TestCargoLockSources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Cargo.toml": {Data: []byte(`[package]
+name = "app"
+
+[dependencies]
+fork = { git = "https://github.com/acme/fork" }
+internal = { version = "1.0", registry = "acme" }
+shared = { path = "../shared" }
+`)},
+		"Cargo.lock": {Data: []byte(`version = 4
+
+[[package]]
+name = "fork"
+version = "0.1.0"
+source = "git+https://github.com/acme/fork#0a1b2c3d"
+
+[[package]]
+name = "internal"
+version = "1.0.2"
+source = "sparse+https://cargo.acme.example/index/"
+checksum = "4a5f"
+
+[[package]]
+name = "shared"
+version = "0.2.0"
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeRust, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	d, _ := m.Get("fork")
+	assert.Equal(t, "git+https://github.com/acme/fork#0a1b2c3d", d.Source)
+	assert.Empty(t, d.Registry)
+
+	d, _ = m.Get("internal")
+	assert.Equal(t, "https://cargo.acme.example/index/", d.Registry)
+	assert.Equal(t, "sha256:4a5f", d.Checksum)
+
+	d, _ = m.Get("shared")
+	assert.Equal(t, "path+../shared", d.Source)
+	assert.Empty(t, d.Registry)
+}
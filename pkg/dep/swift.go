@@ -0,0 +1,458 @@
+package dep
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// swiftManager reads Swift Package Manager and CocoaPods dependencies.
+type swiftManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	diagnostics
+}
+
+func newSwiftManager(fsys fs.FS, path string) Manager {
+	return &swiftManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *swiftManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+func (m *swiftManager) parse() error {
+	m.deps = make(map[string]Dependency)
+	if err := m.parseSwiftPM(); err != nil {
+		return err
+	}
+	return m.parseCocoaPods()
+}
+
+func (m *swiftManager) parseSwiftPM() error {
+	manifest, err := m.fsys.Open(filepath.Join(m.path, "Package.swift"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		defer manifest.Close()
+		deps, err := parsePackageSwift(manifest)
+		if err != nil {
+			return err
+		}
+		for _, d := range deps {
+			m.deps[d.Name] = d
+		}
+	}
+
+	// Package.resolved is next to Package.swift, or inside an Xcode project or workspace.
+	resolvedPaths := []string{filepath.Join(m.path, "Package.resolved")}
+	for _, pattern := range []string{
+		"*.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+		"*.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved",
+	} {
+		matches, err := fs.Glob(m.fsys, filepath.Join(m.path, pattern))
+		if err != nil {
+			return err
+		}
+		resolvedPaths = append(resolvedPaths, matches...)
+	}
+	for _, p := range resolvedPaths {
+		var resolved swiftPackageResolved
+		if err := parseJSON(m.fsys, filepath.Dir(p), filepath.Base(p), &resolved); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if m.report(err) != nil {
+				return err
+			}
+			continue
+		}
+		for _, pin := range resolved.pins() {
+			name := pin.identity()
+			if d, ok := m.deps[name]; ok {
+				// Update existing dependency (preserve IsDirect status)
+				d.Version = pin.State.Version
+				if d.Source == "" || strings.HasPrefix(d.Source, "git+") {
+					d.Source = pin.source()
+				}
+				m.deps[name] = d
+			} else {
+				// New dependency only in the resolved file (indirect)
+				m.deps[name] = Dependency{
+					Name:     name,
+					Version:  pin.State.Version,
+					ToolName: "swiftpm",
+					Source:   pin.source(),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (m *swiftManager) parseCocoaPods() error {
+	podfile, err := m.fsys.Open(filepath.Join(m.path, "Podfile"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		defer podfile.Close()
+		deps, err := parsePodfile(podfile)
+		if err != nil {
+			return err
+		}
+		for _, d := range deps {
+			if existing, ok := m.deps[d.Name]; ok && (existing.ToolName != "cocoapods" || !existing.IsDevOnly) {
+				continue // Regular dependencies take precedence over dev dependencies
+			}
+			m.deps[d.Name] = d
+		}
+	}
+
+	podfileLock, err := m.fsys.Open(filepath.Join(m.path, "Podfile.lock"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer podfileLock.Close()
+	versions, err := parsePodfileLock(podfileLock)
+	if err != nil {
+		return m.report(newDiagnostic(filepath.Join(m.path, "Podfile.lock"), nil, err))
+	}
+	for name, version := range versions {
+		if d, ok := m.deps[name]; ok {
+			if d.ToolName == "cocoapods" {
+				d.Version = version
+				m.deps[name] = d
+			}
+		} else {
+			m.deps[name] = Dependency{
+				Name:     name,
+				Version:  version,
+				ToolName: "cocoapods",
+			}
+		}
+	}
+	return nil
+}
+
+func (m *swiftManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *swiftManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// swiftPackageResolved is the Package.resolved file. Version 1 nests the pins in an "object", and identifies them by
+// repository URL. Versions 2 and 3 have an "identity" for each pin.
+type swiftPackageResolved struct {
+	Version int        `json:"version"`
+	Pins    []swiftPin `json:"pins"`
+	Object  struct {
+		Pins []swiftPin `json:"pins"`
+	} `json:"object"`
+}
+
+func (r swiftPackageResolved) pins() []swiftPin {
+	if r.Version == 1 {
+		return r.Object.Pins
+	}
+	return r.Pins
+}
+
+type swiftPin struct {
+	Identity      string `json:"identity"`
+	Kind          string `json:"kind"`
+	Location      string `json:"location"`
+	RepositoryURL string `json:"repositoryURL"` // Version 1
+	State         struct {
+		Branch   string `json:"branch"`
+		Revision string `json:"revision"`
+		Version  string `json:"version"`
+	} `json:"state"`
+}
+
+func (p swiftPin) identity() string {
+	if p.Identity != "" {
+		return p.Identity
+	}
+	return swiftPackageIdentity(p.RepositoryURL)
+}
+
+func (p swiftPin) source() string {
+	location := p.Location
+	if location == "" {
+		location = p.RepositoryURL
+	}
+	switch {
+	case p.Kind == "registry" || location == "":
+		return ""
+	case p.Kind == "fileSystem" || p.Kind == "localSourceControl":
+		return "path+" + location
+	case p.State.Version == "" && p.State.Revision != "":
+		return "git+" + location + "#" + p.State.Revision
+	}
+	return "git+" + location
+}
+
+// swiftPackageIdentity returns the identity that SwiftPM derives from a package URL or path: its last path component,
+// in lower case, without the ".git" extension.
+func swiftPackageIdentity(location string) string {
+	return strings.ToLower(strings.TrimSuffix(path.Base(strings.TrimSuffix(location, "/")), ".git"))
+}
+
+var (
+	swiftPackagePatt = regexp.MustCompile(`\.package\s*\(`)
+	swiftLabelPatt   = regexp.MustCompile(`^(\w+):\s*(.+)$`)
+	swiftCallPatt    = regexp.MustCompile(`^\.(\w+)\s*\((?:\w+:\s*)?"([^"]*)"\s*\)$`)
+	swiftRangePatt   = regexp.MustCompile(`^"([^"]*)"\s*\.\.([.<])\s*"([^"]*)"$`)
+)
+
+// parsePackageSwift parses the package dependencies in a Package.swift manifest.
+// See: https://developer.apple.com/documentation/packagedescription/package/dependency
+func parsePackageSwift(r io.Reader) ([]Dependency, error) {
+	var b strings.Builder
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		b.WriteString(stripLineComment(scanner.Text(), "//"))
+		b.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	content := b.String()
+
+	var deps []Dependency
+	for _, loc := range swiftPackagePatt.FindAllStringIndex(content, -1) {
+		end := matchingBracket(content, loc[1]-1)
+		if end == -1 {
+			continue
+		}
+		if d, ok := parseSwiftPackageArgs(content[loc[1]:end]); ok {
+			deps = append(deps, d)
+		}
+	}
+	return deps, nil
+}
+
+// parseSwiftPackageArgs parses the arguments of a .package() call, e.g.
+// `url: "https://github.com/vapor/vapor.git", from: "4.89.0"`.
+func parseSwiftPackageArgs(args string) (Dependency, bool) {
+	d := Dependency{IsDirect: true, ToolName: "swiftpm"}
+	var url, fragment string
+	for _, arg := range splitRubyArgs(args) {
+		if matches := swiftRangePatt.FindStringSubmatch(arg); matches != nil {
+			d.Constraint = swiftRange(matches[1], matches[2], matches[3])
+			continue
+		}
+		if matches := swiftCallPatt.FindStringSubmatch(arg); matches != nil {
+			d.Constraint, fragment = swiftRequirement(matches[1], matches[2])
+			continue
+		}
+		matches := swiftLabelPatt.FindStringSubmatch(arg)
+		if matches == nil {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(matches[2]), `"`)
+		switch matches[1] {
+		case "url":
+			url = value
+			d.Name = swiftPackageIdentity(value)
+		case "path":
+			d.Name = swiftPackageIdentity(value)
+			d.Source = "path+" + value
+		case "id":
+			d.Name = strings.ToLower(value) // A registry identifier, e.g. "mona.LinkedList"
+		case "from", "exact", "branch", "revision":
+			d.Constraint, fragment = swiftRequirement(matches[1], value)
+		}
+	}
+	if d.Name == "" {
+		return d, false
+	}
+	if url != "" {
+		d.Source = "git+" + url
+		if fragment != "" {
+			d.Source += "#" + fragment
+		}
+	}
+	return d, true
+}
+
+// swiftRequirement converts a labeled version requirement to a constraint, or a branch or revision to a fragment.
+func swiftRequirement(label, value string) (constraint, fragment string) {
+	switch label {
+	case "from", "upToNextMajor":
+		return "^" + value, ""
+	case "upToNextMinor":
+		return "~" + value, ""
+	case "exact":
+		return value, ""
+	case "branch", "revision":
+		return "", value
+	}
+	return "", ""
+}
+
+// swiftRange converts a closed ("...") or half-open ("..<") range to a constraint.
+func swiftRange(lower, operator, upper string) string {
+	if operator == "." {
+		return ">= " + lower + ", <= " + upper
+	}
+	return ">= " + lower + ", < " + upper
+}
+
+var (
+	podPatt      = regexp.MustCompile(`^pod\s*\(?\s*['"]([^'"]+)['"]\s*,?\s*(.*?)\)?$`)
+	podBlockPatt = regexp.MustCompile(`^(?:(\w+)\s*\(?\s*['"]([^'"]*)['"]\s*\)?|.*)\s+do(?:\s*\|[^|]*\|)?$`)
+)
+
+// parsePodfile parses pods from a CocoaPods Podfile. Pods that are only used in test targets, or in the Debug
+// configuration, are considered development dependencies.
+// See: https://guides.cocoapods.org/syntax/podfile.html
+func parsePodfile(r io.Reader) ([]Dependency, error) {
+	var deps []Dependency
+	var targets []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i != -1 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "end" {
+			if len(targets) > 0 {
+				targets = targets[:len(targets)-1]
+			}
+			continue
+		}
+		if matches := podBlockPatt.FindStringSubmatch(line); matches != nil {
+			var target string
+			if matches[1] == "target" || matches[1] == "abstract_target" {
+				target = matches[2]
+			}
+			targets = append(targets, target)
+			continue
+		}
+		if startsRubyBlock(line) {
+			targets = append(targets, "")
+			continue
+		}
+
+		matches := podPatt.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		constraints, options := parseGemArgs(matches[2])
+		var source string
+		if v, ok := options["git"]; ok {
+			source = gemSource("git", unquoteRuby(v))
+		} else if v, ok := options["path"]; ok {
+			source = gemSource("path", unquoteRuby(v))
+		}
+		var configurations []string
+		for _, k := range []string{"configuration", "configurations"} {
+			if v, ok := options[k]; ok {
+				for _, q := range gemQuotedPatt.FindAllStringSubmatch(v, -1) {
+					configurations = append(configurations, q[1])
+				}
+			}
+		}
+		deps = append(deps, Dependency{
+			Name:       matches[1],
+			Constraint: strings.Join(constraints, ", "),
+			IsDirect:   true,
+			IsDevOnly:  isTestPodTarget(targets) || isDebugOnlyPod(configurations),
+			ToolName:   "cocoapods",
+			Source:     source,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// isTestPodTarget checks if the innermost named target is a test target.
+func isTestPodTarget(targets []string) bool {
+	for i := len(targets) - 1; i >= 0; i-- {
+		if targets[i] != "" {
+			return strings.HasSuffix(targets[i], "Tests")
+		}
+	}
+	return false
+}
+
+func isDebugOnlyPod(configurations []string) bool {
+	if len(configurations) == 0 {
+		return false
+	}
+	for _, c := range configurations {
+		if !strings.EqualFold(c, "debug") {
+			return false
+		}
+	}
+	return true
+}
+
+var podLockPatt = regexp.MustCompile(`^(\S+) \(([^)]+)\)$`)
+
+// parsePodfileLock parses installed pod versions from the PODS section of a Podfile.lock.
+func parsePodfileLock(r io.Reader) (map[string]string, error) {
+	var lock struct {
+		Pods []any `yaml:"PODS"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&lock); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	addPod := func(s string) {
+		if matches := podLockPatt.FindStringSubmatch(s); matches != nil {
+			versions[matches[1]] = matches[2]
+		}
+	}
+	for _, pod := range lock.Pods {
+		switch v := pod.(type) {
+		case string:
+			addPod(v)
+		case map[string]any: // A pod with its own dependencies
+			for k := range v {
+				addPod(k)
+			}
+		}
+	}
+	return versions, nil
+}
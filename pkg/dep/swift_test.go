@@ -0,0 +1,226 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestSwiftPackageManager(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Package.swift": {Data: []byte(`// swift-tools-version:5.9
+import PackageDescription
+
+let package = Package(
+    name: "App",
+    platforms: [.macOS(.v13)],
+    dependencies: [
+        // 💧 A server-side Swift web framework.
+        .package(url: "https://github.com/vapor/vapor.git", from: "4.89.0"),
+        .package(url: "https://github.com/vapor/fluent.git", .upToNextMinor(from: "4.8.0")),
+        .package(
+            url: "https://github.com/apple/swift-nio.git",
+            "2.60.0"..<"3.0.0"
+        ),
+        .package(url: "https://github.com/example/Tools", branch: "main"),
+        .package(path: "../SharedModels"),
+        // .package(url: "https://github.com/example/unused.git", from: "1.0.0"),
+    ],
+    targets: [
+        .executableTarget(name: "App", dependencies: [.product(name: "Vapor", package: "vapor")]),
+    ]
+)
+`)},
+		"Package.resolved": {Data: []byte(`{
+  "originHash" : "2b4c1f0a",
+  "pins" : [
+    {
+      "identity" : "vapor",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/vapor/vapor.git",
+      "state" : {
+        "revision" : "4014016aad591a120f244f9b393e8a57e1ab8a43",
+        "version" : "4.89.3"
+      }
+    },
+    {
+      "identity" : "tools",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/example/Tools",
+      "state" : {
+        "branch" : "main",
+        "revision" : "b5ea8a2e49c8b8d5f2a4a05ce2a1d2c1e5bfc5d7"
+      }
+    },
+    {
+      "identity" : "swift-log",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/apple/swift-log.git",
+      "state" : {
+        "revision" : "532d8b529501fb73a2455b179e0bbb6d49b652ed",
+        "version" : "1.5.3"
+      }
+    }
+  ],
+  "version" : 3
+}
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeSwift, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	toGet := []struct {
+		name       string
+		dependency dep.Dependency
+		found      bool
+	}{
+		{name: "vapor", dependency: dep.Dependency{
+			Name:       "vapor",
+			Constraint: "^4.89.0",
+			Version:    "4.89.3",
+			IsDirect:   true,
+			ToolName:   "swiftpm",
+			Source:     "git+https://github.com/vapor/vapor.git",
+		}, found: true},
+		{name: "fluent", dependency: dep.Dependency{
+			Name:       "fluent",
+			Constraint: "~4.8.0",
+			IsDirect:   true,
+			ToolName:   "swiftpm",
+			Source:     "git+https://github.com/vapor/fluent.git",
+		}, found: true},
+		{name: "swift-nio", dependency: dep.Dependency{
+			Name:       "swift-nio",
+			Constraint: ">= 2.60.0, < 3.0.0",
+			IsDirect:   true,
+			ToolName:   "swiftpm",
+			Source:     "git+https://github.com/apple/swift-nio.git",
+		}, found: true},
+		{name: "tools", dependency: dep.Dependency{
+			Name:     "tools",
+			IsDirect: true,
+			ToolName: "swiftpm",
+			Source:   "git+https://github.com/example/Tools#b5ea8a2e49c8b8d5f2a4a05ce2a1d2c1e5bfc5d7",
+		}, found: true},
+		{name: "sharedmodels", dependency: dep.Dependency{
+			Name:     "sharedmodels",
+			IsDirect: true,
+			ToolName: "swiftpm",
+			Source:   "path+../SharedModels",
+		}, found: true},
+		{name: "swift-log", dependency: dep.Dependency{
+			Name:     "swift-log",
+			Version:  "1.5.3",
+			ToolName: "swiftpm",
+			Source:   "git+https://github.com/apple/swift-log.git",
+		}, found: true},
+		{name: "unused"},
+	}
+	for _, c := range toGet {
+		d, ok := m.Get(c.name)
+		assert.Equal(t, c.found, ok, c.name)
+		assert.Equal(t, c.dependency, d, c.name)
+	}
+}
+
+func TestSwiftPackageResolvedV1(t *testing.T) {
+	fsys := fstest.MapFS{
+		"App.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved": {Data: []byte(`{
+  "object": {
+    "pins": [
+      {
+        "package": "Alamofire",
+        "repositoryURL": "https://github.com/Alamofire/Alamofire.git",
+        "state": {
+          "branch": null,
+          "revision": "f82c23a8a7ef8dc1a49a8bfc6a96883e79121864",
+          "version": "5.8.1"
+        }
+      }
+    ]
+  },
+  "version": 1
+}
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeSwift, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.Equal(t, []dep.Dependency{{
+		Name:     "alamofire",
+		Version:  "5.8.1",
+		ToolName: "swiftpm",
+		Source:   "git+https://github.com/Alamofire/Alamofire.git",
+	}}, m.Find("alamo*"))
+}
+
+func TestCocoaPods(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Podfile": {Data: []byte(`platform :ios, '15.0'
+use_frameworks!
+
+target 'MyApp' do
+  pod 'Alamofire', '~> 5.8'
+  pod 'Firebase/Analytics'
+  pod 'SwiftLint', :configurations => ['Debug']
+  pod 'Networking', :git => 'https://github.com/example/Networking.git', :tag => '2.0.0'
+
+  target 'MyAppTests' do
+    inherit! :search_paths
+    pod 'Quick', '~> 7.0'
+    pod 'Alamofire', '~> 5.8'
+  end
+end
+
+post_install do |installer|
+  installer.pods_project.targets.each do |target|
+    puts target.name
+  end
+end
+`)},
+		"Podfile.lock": {Data: []byte(`PODS:
+  - Alamofire (5.8.1)
+  - Firebase/Analytics (10.18.0):
+    - Firebase/Core
+  - Firebase/Core (10.18.0)
+  - Networking (2.0.0)
+  - Quick (7.3.0)
+  - SwiftLint (0.54.0)
+
+DEPENDENCIES:
+  - Alamofire (~> 5.8)
+  - Firebase/Analytics
+  - Quick (~> 7.0)
+  - SwiftLint
+
+COCOAPODS: 1.14.3
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeSwift, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "Alamofire", Constraint: "~> 5.8", Version: "5.8.1", IsDirect: true, ToolName: "cocoapods"},
+		{Name: "Firebase/Analytics", Version: "10.18.0", IsDirect: true, ToolName: "cocoapods"},
+		{Name: "Firebase/Core", Version: "10.18.0", ToolName: "cocoapods"},
+		{
+			Name:     "Networking",
+			Version:  "2.0.0",
+			IsDirect: true,
+			ToolName: "cocoapods",
+			Source:   "git+https://github.com/example/Networking.git",
+		},
+		{Name: "Quick", Constraint: "~> 7.0", Version: "7.3.0", IsDirect: true, IsDevOnly: true, ToolName: "cocoapods"},
+		{Name: "SwiftLint", Version: "0.54.0", IsDirect: true, IsDevOnly: true, ToolName: "cocoapods"},
+	}, m.Find("*"))
+}
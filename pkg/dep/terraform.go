@@ -0,0 +1,362 @@
+package dep
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/IGLOU-EU/go-wildcard/v2"
+)
+
+// terraformManager reads Terraform (or OpenTofu) providers and modules.
+// Providers are named by their source address (e.g. "hashicorp/aws"), and registry modules by their module address
+// (e.g. "terraform-aws-modules/vpc/aws"), with the registry URL. Other remote modules are named by their URL.
+type terraformManager struct {
+	fsys fs.FS
+	path string
+
+	initOnce sync.Once
+	deps     map[string]Dependency
+	platform []Dependency
+	diagnostics
+}
+
+func newTerraformManager(fsys fs.FS, path string) Manager {
+	return &terraformManager{
+		fsys: fsys,
+		path: path,
+	}
+}
+
+func (m *terraformManager) Init() error {
+	var err error
+	m.initOnce.Do(func() {
+		err = m.parse()
+	})
+	return err
+}
+
+func (m *terraformManager) parse() error {
+	m.deps = make(map[string]Dependency)
+
+	files, err := fs.Glob(m.fsys, filepath.Join(m.path, "*.tf"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		blocks, err := m.parseHCLFile(filename)
+		if err != nil {
+			return err
+		}
+		m.addConfiguration(blocks)
+	}
+
+	lockPath := filepath.Join(m.path, ".terraform.lock.hcl")
+	if _, err := fs.Stat(m.fsys, lockPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	blocks, err := m.parseHCLFile(lockPath)
+	if err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		if b.typ != "provider" || len(b.labels) == 0 {
+			continue
+		}
+		name := terraformProviderName(b.labels[0])
+		if d, ok := m.deps[name]; ok {
+			// Update existing dependency (preserve IsDirect status)
+			d.Version = b.attrs["version"]
+			m.deps[name] = d
+		} else {
+			// New dependency only in the lock file (indirect), e.g. a provider required by a module
+			m.deps[name] = Dependency{
+				Vendor:     terraformVendor(name),
+				Name:       name,
+				Constraint: b.attrs["constraints"],
+				Version:    b.attrs["version"],
+				ToolName:   "terraform",
+			}
+		}
+	}
+	return nil
+}
+
+func (m *terraformManager) parseHCLFile(filename string) ([]hclBlock, error) {
+	f, err := m.fsys.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseHCLBlocks(f)
+}
+
+// addConfiguration adds providers from "required_providers" blocks, modules, and the "required_version".
+func (m *terraformManager) addConfiguration(blocks []hclBlock) {
+	for _, b := range blocks {
+		switch b.typ {
+		case "terraform":
+			if v, ok := b.attrs["required_version"]; ok {
+				m.platform = append(m.platform, Dependency{Name: "terraform", Constraint: v, ToolName: "terraform"})
+			}
+			for _, child := range b.children {
+				if child.typ != "required_providers" {
+					continue
+				}
+				for localName, v := range child.attrs {
+					// Legacy syntax: a version constraint for a provider in the default namespace.
+					m.addProvider(terraformProviderName(localName), v)
+				}
+				for _, p := range child.children {
+					source := p.attrs["source"]
+					if source == "" {
+						source = p.typ
+					}
+					m.addProvider(terraformProviderName(source), p.attrs["version"])
+				}
+			}
+		case "module":
+			m.addModule(b.attrs["source"], b.attrs["version"])
+		}
+	}
+}
+
+func (m *terraformManager) addProvider(name, constraint string) {
+	if _, exists := m.deps[name]; exists {
+		return
+	}
+	m.deps[name] = Dependency{
+		Vendor:     terraformVendor(name),
+		Name:       name,
+		Constraint: constraint,
+		IsDirect:   true,
+		ToolName:   "terraform",
+	}
+}
+
+// addModule adds a module from the registry, or from a remote source such as Git. Local modules are skipped.
+// See: https://developer.hashicorp.com/terraform/language/modules/sources
+func (m *terraformManager) addModule(source, version string) {
+	if source == "" || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return
+	}
+	d := Dependency{
+		Name:       source,
+		Constraint: version,
+		IsDirect:   true,
+		ToolName:   "terraform",
+	}
+	if rest, ok := strings.CutPrefix(source, "git::"); ok {
+		url, ref, _ := strings.Cut(rest, "?ref=")
+		d.Name = url
+		d.Source = "git+" + url
+		if ref != "" {
+			d.Source += "#" + ref
+		}
+	} else if isTerraformRegistryModule(source) {
+		d.Name = strings.TrimPrefix(source, "registry.terraform.io/")
+		d.Vendor = terraformVendor(d.Name)
+		d.Registry = "https://registry.terraform.io/"
+		if parts := strings.Split(d.Name, "/"); len(parts) == 4 {
+			d.Registry = "https://" + parts[0] + "/"
+		}
+	}
+	if _, exists := m.deps[d.Name]; !exists {
+		m.deps[d.Name] = d
+	}
+}
+
+// isTerraformRegistryModule checks if a module source is a registry address, in the format
+// "[hostname/]namespace/name/provider".
+func isTerraformRegistryModule(source string) bool {
+	if strings.Contains(source, "::") || strings.Contains(source, "://") {
+		return false
+	}
+	parts := strings.Split(source, "/")
+	switch len(parts) {
+	case 3:
+		return parts[0] != "github.com" && parts[0] != "bitbucket.org"
+	case 4:
+		return strings.Contains(parts[0], ".")
+	}
+	return false
+}
+
+// terraformProviderName normalizes a provider source address, e.g. "registry.terraform.io/hashicorp/aws" or "aws"
+// to "hashicorp/aws".
+func terraformProviderName(source string) string {
+	source = strings.ToLower(source)
+	parts := strings.Split(source, "/")
+	switch len(parts) {
+	case 1:
+		return "hashicorp/" + source
+	case 3:
+		if parts[0] == "registry.terraform.io" || parts[0] == "registry.opentofu.org" {
+			return parts[1] + "/" + parts[2]
+		}
+	}
+	return source
+}
+
+func terraformVendor(name string) string {
+	vendor, _, _ := strings.Cut(name, "/")
+	return vendor
+}
+
+func (m *terraformManager) Get(name string) (Dependency, bool) {
+	dep, ok := m.deps[name]
+	return dep, ok
+}
+
+func (m *terraformManager) Find(pattern string) []Dependency {
+	var deps []Dependency
+	for name, dep := range m.deps {
+		if wildcard.Match(pattern, name) {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Platform returns the "required_version" constraint for Terraform itself, if any.
+func (m *terraformManager) Platform() []Dependency {
+	return m.platform
+}
+
+// hclBlock is a simplified HCL block, with its string attributes and nested blocks.
+// Object attributes such as `aws = { source = "hashicorp/aws" }` are also represented as blocks.
+type hclBlock struct {
+	typ      string
+	labels   []string
+	attrs    map[string]string
+	children []hclBlock
+}
+
+var (
+	hclBlockPatt  = regexp.MustCompile(`^([\w-]+)((?:\s+"[^"]*")*)\s*(?:=\s*)?\{\s*(.*?)\s*(\}?)$`)
+	hclAttrPatt   = regexp.MustCompile(`^([\w-]+)\s*=\s*(.*)$`)
+	hclStringPatt = regexp.MustCompile(`^"((?:[^"\\]|\\.)*)"`)
+	hclLabelPatt  = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// parseHCLBlocks parses the blocks and string attributes in an HCL file, such as a Terraform configuration or lock
+// file. It does not evaluate expressions: attributes that are not string literals are ignored.
+func parseHCLBlocks(r io.Reader) ([]hclBlock, error) {
+	root := &hclBlock{}
+	stack := []*hclBlock{root}
+	var exprDepth int // The depth of brackets opened in a multi-line attribute expression.
+	var heredoc string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if heredoc != "" {
+			if strings.TrimSpace(scanner.Text()) == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		line := strings.TrimSpace(stripLineComment(scanner.Text(), "#", "//"))
+		if line == "" {
+			continue
+		}
+		if exprDepth > 0 {
+			exprDepth += hclBracketDepth(line)
+			continue
+		}
+		current := stack[len(stack)-1]
+
+		if strings.HasPrefix(line, "}") {
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, *current)
+			}
+			continue
+		}
+
+		if matches := hclBlockPatt.FindStringSubmatch(line); matches != nil {
+			block := hclBlock{typ: matches[1], attrs: make(map[string]string)}
+			for _, l := range hclLabelPatt.FindAllStringSubmatch(matches[2], -1) {
+				block.labels = append(block.labels, l[1])
+			}
+			if matches[4] == "}" {
+				// A single-line block or object, e.g. `aws = { source = "hashicorp/aws", version = "~> 5.0" }`.
+				for _, attr := range splitRubyArgs(matches[3]) {
+					addHCLAttr(&block, attr)
+				}
+				current.children = append(current.children, block)
+				continue
+			}
+			stack = append(stack, &block)
+			continue
+		}
+
+		if matches := hclAttrPatt.FindStringSubmatch(line); matches != nil {
+			// Skip multi-line expressions, e.g. lists or `merge(var.tags, {`, so that their closing brackets are not
+			// taken as the end of a block.
+			if depth := hclBracketDepth(matches[2]); depth > 0 {
+				exprDepth = depth
+				continue
+			}
+			if marker, ok := strings.CutPrefix(matches[2], "<<"); ok {
+				heredoc = strings.TrimPrefix(marker, "-")
+				continue
+			}
+			addHCLAttr(current, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Close any unterminated blocks.
+	for len(stack) > 1 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		stack[len(stack)-1].children = append(stack[len(stack)-1].children, *current)
+	}
+	return root.children, nil
+}
+
+// hclBracketDepth returns the number of brackets, braces and parentheses opened minus those closed in an expression,
+// ignoring any in string literals.
+func hclBracketDepth(expr string) int {
+	var depth int
+	var inString, escaped bool
+	for _, c := range expr {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+		}
+	}
+	return depth
+}
+
+// addHCLAttr adds an attribute to a block, if its value is a string literal.
+func addHCLAttr(b *hclBlock, attr string) {
+	matches := hclAttrPatt.FindStringSubmatch(strings.TrimSpace(attr))
+	if matches == nil {
+		return
+	}
+	if s := hclStringPatt.FindStringSubmatch(matches[2]); s != nil {
+		if b.attrs == nil {
+			b.attrs = make(map[string]string)
+		}
+		b.attrs[matches[1]] = s[1]
+	}
+}
@@ -0,0 +1,197 @@
+package dep_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestTerraform(t *testing.T) {
+	fsys := fstest.MapFS{
+		"versions.tf": {Data: []byte(`terraform {
+  required_version = ">= 1.5.0" # CI uses the latest
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    cloudflare = { source = "cloudflare/cloudflare", version = ">= 4.20" }
+    random = "~> 3.5"
+  }
+}
+`)},
+		"main.tf": {Data: []byte(`// The network.
+module "vpc" {
+  # The closing brackets of a multi-line expression do not end the block.
+  tags = merge(var.tags, {
+    Name = "main {"
+  })
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.4.0"
+
+  name = "main"
+  cidr = "10.0.0.0/16"
+  azs  = ["eu-west-1a", "eu-west-1b"]
+  private_subnets = [
+    "10.0.1.0/24",
+    "10.0.2.0/24",
+  ]
+}
+
+module "dns" {
+  source = "git::https://example.com/terraform/dns.git?ref=v1.2.0"
+}
+
+module "app" {
+  source = "./modules/app"
+}
+
+resource "aws_iam_policy" "deploy" {
+  name   = "deploy"
+  policy = <<-EOT
+    {
+      "Version": "2012-10-17"
+    }
+  EOT
+}
+`)},
+		".terraform.lock.hcl": {Data: []byte(`# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = ">= 4.35.0, ~> 5.0"
+  hashes = [
+    "h1:ltxyuBWIy9cq0kIKDJH1jeWJy/y7XJLjS4QrsQK4plA=",
+    "zh:0cdb9c2083bf0902442384f7309367791e4640581652dda456f2d6d7abf0de8d",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version     = "3.6.0"
+  constraints = "~> 3.5"
+}
+
+provider "registry.terraform.io/hashicorp/tls" {
+  version     = "4.0.5"
+  constraints = ">= 3.0.0"
+}
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeTerraform, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.ElementsMatch(t, []dep.Dependency{
+		{
+			Vendor:     "hashicorp",
+			Name:       "hashicorp/aws",
+			Constraint: "~> 5.0",
+			Version:    "5.31.0",
+			IsDirect:   true,
+			ToolName:   "terraform",
+		},
+		{
+			Vendor:     "cloudflare",
+			Name:       "cloudflare/cloudflare",
+			Constraint: ">= 4.20",
+			IsDirect:   true,
+			ToolName:   "terraform",
+		},
+		{
+			Vendor:     "hashicorp",
+			Name:       "hashicorp/random",
+			Constraint: "~> 3.5",
+			Version:    "3.6.0",
+			IsDirect:   true,
+			ToolName:   "terraform",
+		},
+		{
+			Vendor:     "hashicorp",
+			Name:       "hashicorp/tls",
+			Constraint: ">= 3.0.0",
+			Version:    "4.0.5",
+			ToolName:   "terraform",
+		},
+		{
+			Vendor:     "terraform-aws-modules",
+			Name:       "terraform-aws-modules/vpc/aws",
+			Constraint: "5.4.0",
+			IsDirect:   true,
+			ToolName:   "terraform",
+			Registry:   "https://registry.terraform.io/",
+		},
+		{
+			Name:     "https://example.com/terraform/dns.git",
+			IsDirect: true,
+			ToolName: "terraform",
+			Source:   "git+https://example.com/terraform/dns.git#v1.2.0",
+		},
+	}, m.Find("*"))
+
+	pm, ok := m.(dep.PlatformManager)
+	require.True(t, ok)
+	assert.Equal(t, []dep.Dependency{{Name: "terraform", Constraint: ">= 1.5.0", ToolName: "terraform"}}, pm.Platform())
+}
+
+func TestHelm(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Chart.yaml": {Data: []byte(`apiVersion: v2
+name: shop
+version: 0.3.0
+appVersion: "2.1.0"
+kubeVersion: ">= 1.26.0-0"
+dependencies:
+  - name: postgresql
+    version: "13.x.x"
+    repository: "oci://registry-1.docker.io/bitnamicharts"
+    condition: postgresql.enabled
+  - name: redis
+    version: ~18.6.0
+    repository: https://charts.bitnami.com/bitnami
+  - name: common
+    version: 0.1.0
+    repository: file://../common
+`)},
+		"Chart.lock": {Data: []byte(`dependencies:
+- name: postgresql
+  repository: oci://registry-1.docker.io/bitnamicharts
+  version: 13.2.24
+- name: redis
+  repository: https://charts.bitnami.com/bitnami
+  version: 18.6.1
+- name: common
+  repository: file://../common
+  version: 0.1.0
+digest: sha256:5c2c2b7f8e1c8f5a4c6a9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c
+generated: "2024-01-15T10:00:00.000000+01:00"
+`)},
+	}
+
+	m, err := dep.GetManager(dep.ManagerTypeHelm, fsys, ".")
+	require.NoError(t, err)
+	require.NoError(t, m.Init())
+
+	assert.ElementsMatch(t, []dep.Dependency{
+		{Name: "postgresql", Constraint: "13.x.x", Version: "13.2.24", IsDirect: true, ToolName: "helm"},
+		{Name: "redis", Constraint: "~18.6.0", Version: "18.6.1", IsDirect: true, ToolName: "helm"},
+		{
+			Name:       "common",
+			Constraint: "0.1.0",
+			Version:    "0.1.0",
+			IsDirect:   true,
+			ToolName:   "helm",
+			Source:     "path+../common",
+		},
+	}, m.Find("*"))
+
+	pm, ok := m.(dep.PlatformManager)
+	require.True(t, ok)
+	assert.Equal(t, []dep.Dependency{{Name: "kubernetes", Constraint: ">= 1.26.0-0", ToolName: "helm"}}, pm.Platform())
+}
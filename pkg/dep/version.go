@@ -0,0 +1,466 @@
+package dep
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidVersion is returned when a version cannot be parsed.
+var ErrInvalidVersion = errors.New("invalid version")
+
+// ErrInvalidConstraint is returned when a version constraint cannot be parsed.
+var ErrInvalidConstraint = errors.New("invalid version constraint")
+
+// CompareVersions compares two versions, returning -1 if a is lower than b, 0 if they are equal, or 1 if a is
+// greater than b.
+//
+// Versions are compared by their numeric release segments (missing segments count as zero), and then by their
+// pre-release or qualifier identifiers, in a way that suits most ecosystems: for example "1.0.dev1" < "1.0a1" <
+// "1.0-beta.2" < "1.0-rc1" < "1.0" = "1.0.0.RELEASE" < "1.0.post1". Build metadata (after "+") is ignored.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.compare(vb), nil
+}
+
+// SatisfiesConstraint checks if a version satisfies a constraint, written in the syntax of the given manager type:
+//   - "js", "go", "dart", "swift", "helm", "cpp", "docker" and "github-actions": npm-style semver ranges, e.g.
+//     "^1.2", "~1.2.3", "1.x", ">=1.0 <2.0", "1.0 - 2.0" or "^1 || ^2"
+//   - "php": Composer constraints, where "~1.2" means ">=1.2 <2.0", e.g. "^10.0", "~1.2", "1.0.*" or "^7.4|^8.0"
+//   - "python": PEP 440 specifiers, e.g. ">=3.2,<4", "~=2.2" or "==1.4.*"
+//   - "ruby", "terraform" and "elixir": pessimistic constraints, e.g. "~> 2.2", ">= 1.0, < 3" or "~> 1.0 or ~> 2.0"
+//   - "rust": Cargo requirements, where a bare version means a caret requirement
+//   - "java" and "dotnet": Maven and NuGet ranges, e.g. "[1.0,2.0)" or "(,1.0],[1.2,)"
+//
+// Comparison operators (">=", "<", etc.) are accepted in all syntaxes.
+func SatisfiesConstraint(managerType, version, constraint string) (bool, error) {
+	c, err := parseConstraint(managerType, constraint)
+	if err != nil {
+		return false, err
+	}
+	if managerType == ManagerTypeDocker {
+		// An image tag suffix is a variant rather than a pre-release, e.g. "3.12-slim".
+		version, _, _ = strings.Cut(version, "-")
+	}
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return c.matches(v), nil
+}
+
+// LowerBound returns the lowest release allowed by a constraint, in the syntax of the given manager type (see
+// SatisfiesConstraint), e.g. "3.11" for ">=3.11,<4", or "7.4" for "^7.4|^8.0". Alternatives without a lower bound are
+// ignored, so "18" is returned for "<16 || >=18". An empty string is returned if there is no lower bound, e.g. for
+// "<4" or "*".
+func LowerBound(managerType, constraint string) (string, error) {
+	c, err := parseConstraint(managerType, constraint)
+	if err != nil {
+		return "", err
+	}
+	var lowest *version
+	for _, alternative := range c {
+		var bound *version
+		for _, comp := range alternative {
+			if comp.op != ">=" && comp.op != ">" && comp.op != "=" || slices.Max(comp.v.release) == 0 {
+				continue
+			}
+			if bound == nil || comp.v.compare(*bound) > 0 {
+				bound = &comp.v
+			}
+		}
+		if bound != nil && (lowest == nil || bound.compare(*lowest) < 0) {
+			lowest = bound
+		}
+	}
+	if lowest == nil {
+		return "", nil
+	}
+	parts := make([]string, len(lowest.release))
+	for i, n := range lowest.release {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// version is a parsed version.
+type version struct {
+	release []int
+	// pre contains lower-case pre-release identifiers (e.g. "rc", "1") or qualifiers (e.g. "post", "1").
+	pre []string
+	// min marks the lowest possible version with this release, below all its pre-releases.
+	min bool
+}
+
+var (
+	versionPatt       = regexp.MustCompile(`^[vV]?(\d+(?:\.\d+)*)(.*)$`)
+	versionIdentPatt  = regexp.MustCompile(`[a-z]+|\d+`)
+	releaseQualifiers = []string{"final", "ga", "release"}
+	postQualifiers    = []string{"post", "p", "pl", "sp", "rev", "r"}
+	preQualifierRanks = map[string]int{
+		"dev": 0, "alpha": 1, "a": 1, "beta": 2, "b": 2, "milestone": 3, "m": 3,
+		"pre": 4, "preview": 4, "rc": 4, "c": 4, "cr": 4, "snapshot": 5,
+	}
+)
+
+func parseVersion(s string) (version, error) {
+	s = strings.TrimSpace(s)
+	s, _, _ = strings.Cut(s, "+")
+	matches := versionPatt.FindStringSubmatch(s)
+	if matches == nil {
+		return version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	var v version
+	for _, part := range strings.Split(matches[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+		v.release = append(v.release, n)
+	}
+	v.pre = versionIdentPatt.FindAllString(strings.ToLower(matches[2]), -1)
+	if len(v.pre) > 0 && slices.Contains(releaseQualifiers, v.pre[0]) {
+		v.pre = v.pre[1:]
+	}
+	return v, nil
+}
+
+// preRank returns -1 for a pre-release, 0 for a release, and 1 for a post-release.
+func (v version) preRank() int {
+	switch {
+	case len(v.pre) == 0:
+		return 0
+	case slices.Contains(postQualifiers, v.pre[0]):
+		return 1
+	default:
+		return -1
+	}
+}
+
+func (v version) compare(other version) int {
+	for i := range max(len(v.release), len(other.release)) {
+		var a, b int
+		if i < len(v.release) {
+			a = v.release[i]
+		}
+		if i < len(other.release) {
+			b = other.release[i]
+		}
+		if a != b {
+			return cmp.Compare(a, b)
+		}
+	}
+	if v.min != other.min {
+		if v.min {
+			return -1
+		}
+		return 1
+	}
+	if c := cmp.Compare(v.preRank(), other.preRank()); c != 0 {
+		return c
+	}
+	for i := range min(len(v.pre), len(other.pre)) {
+		if c := compareIdentifiers(v.pre[i], other.pre[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.pre), len(other.pre))
+}
+
+// compareIdentifiers compares pre-release identifiers: numbers are compared numerically and are lower than words,
+// and known words such as "alpha" and "rc" are compared by their rank.
+func compareIdentifiers(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	ra, okA := preQualifierRanks[a]
+	rb, okB := preQualifierRanks[b]
+	if okA && okB {
+		return cmp.Compare(ra, rb)
+	}
+	return strings.Compare(a, b)
+}
+
+// bump returns the lowest version of the next release, incrementing the segment at index i.
+func (v version) bump(i int) version {
+	release := make([]int, i+1)
+	copy(release, v.release)
+	release[i]++
+	return version{release: release, min: true}
+}
+
+// comparator is a single condition in a constraint, e.g. ">=1.2.0".
+type comparator struct {
+	op string // One of "=", "!=", "<", "<=", ">", ">="
+	v  version
+	// prefix compares only the first release segments, for wildcards such as "1.2.*".
+	prefix bool
+}
+
+func (c comparator) matches(v version) bool {
+	if c.prefix {
+		match := len(v.release) >= len(c.v.release) && slices.Equal(v.release[:len(c.v.release)], c.v.release)
+		return match == (c.op == "=")
+	}
+	result := v.compare(c.v)
+	switch c.op {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	}
+	return false
+}
+
+// constraint is a set of alternatives, each of which is a list of comparators that must all match.
+type constraint [][]comparator
+
+func (c constraint) matches(v version) bool {
+	for _, alternative := range c {
+		if !slices.ContainsFunc(alternative, func(c comparator) bool { return !c.matches(v) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraintSyntax describes the differences between the constraint syntaxes of package managers.
+type constraintSyntax struct {
+	// bareOp is the operator implied by a version without one: "=", "^" or ">=".
+	bareOp string
+	// pessimisticTilde means "~1.2" allows "<2.0" (as in Composer), rather than "<1.3" (as in npm).
+	pessimisticTilde bool
+	// partialRange means a partial version is a range, e.g. "1.2" is "1.2.x" (as in npm).
+	partialRange bool
+	// intervals enables Maven and NuGet interval notation, e.g. "[1.0,2.0)".
+	intervals bool
+}
+
+func syntaxFor(managerType string) constraintSyntax {
+	switch managerType {
+	case ManagerTypePHP:
+		return constraintSyntax{bareOp: "=", pessimisticTilde: true}
+	case ManagerTypePython, ManagerTypeRuby, ManagerTypeTerraform, ManagerTypeElixir:
+		return constraintSyntax{bareOp: "="}
+	case ManagerTypeRust:
+		return constraintSyntax{bareOp: "^"}
+	case ManagerTypeJava:
+		return constraintSyntax{bareOp: "=", intervals: true}
+	case ManagerTypeDotnet:
+		return constraintSyntax{bareOp: ">=", intervals: true}
+	default:
+		return constraintSyntax{bareOp: "=", partialRange: true}
+	}
+}
+
+var (
+	constraintOpSpacePatt = regexp.MustCompile(`(===|==|!=|>=|<=|~>|~=|=|<|>|\^|~)\s+`)
+	constraintOrPatt      = regexp.MustCompile(`\|\|?|\s+or\s+`)
+	constraintAndPatt     = regexp.MustCompile(`\s*,\s*|\s+and\s+|\s+`)
+	hyphenRangePatt       = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+	intervalPatt          = regexp.MustCompile(`([\[(])([^\[\]()]*)([\])])`)
+	constraintTermPatt    = regexp.MustCompile(`^(===|==|!=|>=|<=|~>|~=|=|<|>|\^|~)?(.*)$`)
+	stabilityFlagPatt     = regexp.MustCompile(`@\w+$`)
+)
+
+func parseConstraint(managerType, s string) (constraint, error) {
+	syntax := syntaxFor(managerType)
+	s = strings.TrimSpace(s)
+	// Remove PEP 508 environment markers, e.g. `>=1.0; python_version < "3.8"`.
+	s, _, _ = strings.Cut(s, ";")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidConstraint)
+	}
+	if syntax.intervals && strings.ContainsAny(s, "[(") {
+		return parseIntervals(s)
+	}
+	// Conan version ranges are enclosed in brackets, e.g. "[>=1.0 <2.0]".
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		s = s[1 : len(s)-1]
+	}
+
+	var c constraint
+	for _, alternative := range constraintOrPatt.Split(s, -1) {
+		alternative = strings.TrimSpace(alternative)
+		var comparators []comparator
+		if m := hyphenRangePatt.FindStringSubmatch(alternative); m != nil {
+			lower, err := parseConstraintTerm(syntax, ">="+m[1])
+			if err != nil {
+				return nil, err
+			}
+			upper, err := parseConstraintTerm(syntax, "<="+m[2])
+			if err != nil {
+				return nil, err
+			}
+			comparators = append(lower, upper...)
+		} else {
+			alternative = constraintOpSpacePatt.ReplaceAllString(alternative, "$1")
+			for _, term := range constraintAndPatt.Split(alternative, -1) {
+				if term == "" {
+					continue
+				}
+				terms, err := parseConstraintTerm(syntax, term)
+				if err != nil {
+					return nil, err
+				}
+				comparators = append(comparators, terms...)
+			}
+		}
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, s)
+		}
+		c = append(c, comparators)
+	}
+	return c, nil
+}
+
+// parseConstraintTerm parses a single operator and version into comparators.
+func parseConstraintTerm(syntax constraintSyntax, term string) ([]comparator, error) {
+	m := constraintTermPatt.FindStringSubmatch(stabilityFlagPatt.ReplaceAllString(term, ""))
+	op, s := m[1], m[2]
+
+	// Handle wildcards, e.g. "*", "1.x" or "1.2.*".
+	wildcard := s == "*" || s == "x" || s == "X"
+	if !wildcard {
+		for _, suffix := range []string{".*", ".x", ".X"} {
+			if trimmed, ok := strings.CutSuffix(s, suffix); ok {
+				s, wildcard = trimmed, true
+				break
+			}
+		}
+	}
+	if wildcard && (s == "*" || s == "x" || s == "X") {
+		if op == "" || op == "=" || op == "==" || op == ">=" {
+			return []comparator{{op: ">=", v: version{release: []int{0}, min: true}}}, nil
+		}
+		return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, term)
+	}
+
+	v, err := parseVersion(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, term)
+	}
+	if op == "" {
+		op = syntax.bareOp
+	}
+	partial := !wildcard && syntax.partialRange && len(v.release) < 3 && len(v.pre) == 0
+
+	switch op {
+	case "=", "==", "===":
+		if wildcard || partial {
+			return []comparator{{op: "=", v: v, prefix: true}}, nil
+		}
+		return []comparator{{op: "=", v: v}}, nil
+	case "!=":
+		return []comparator{{op: "!=", v: v, prefix: wildcard}}, nil
+	case ">", "<=":
+		if wildcard || partial {
+			// For example, ">1.2.x" means ">=1.3.0", and "<=1.2" means "<1.3.0".
+			next := v.bump(len(v.release) - 1)
+			return []comparator{{op: map[string]string{">": ">=", "<=": "<"}[op], v: next}}, nil
+		}
+		return []comparator{{op: op, v: v}}, nil
+	case ">=", "<":
+		if wildcard || partial {
+			v.min = true
+		}
+		return []comparator{{op: op, v: v}}, nil
+	case "^":
+		return []comparator{{op: ">=", v: v}, {op: "<", v: v.bump(caretIndex(v))}}, nil
+	case "~":
+		if syntax.pessimisticTilde {
+			return pessimistic(v), nil
+		}
+		i := min(1, len(v.release)-1)
+		return []comparator{{op: ">=", v: v}, {op: "<", v: v.bump(i)}}, nil
+	case "~>", "~=":
+		return pessimistic(v), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, term)
+}
+
+// caretIndex returns the index of the segment incremented by a caret constraint: the first non-zero segment, or
+// the last specified segment if all are zero.
+func caretIndex(v version) int {
+	for i, n := range v.release {
+		if n != 0 {
+			return i
+		}
+	}
+	return len(v.release) - 1
+}
+
+// pessimistic returns the comparators of a pessimistic constraint, where the last specified segment may increase,
+// e.g. "~> 2.2" means ">= 2.2, < 3" and "~> 2.2.0" means ">= 2.2.0, < 2.3".
+func pessimistic(v version) []comparator {
+	i := max(0, len(v.release)-2)
+	return []comparator{{op: ">=", v: v}, {op: "<", v: v.bump(i)}}
+}
+
+// parseIntervals parses Maven or NuGet interval notation, where each interval is an alternative.
+// See: https://maven.apache.org/enforcer/enforcer-rules/versionRanges.html
+func parseIntervals(s string) (constraint, error) {
+	matches := intervalPatt.FindAllStringSubmatch(s, -1)
+	if matches == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, s)
+	}
+	var c constraint
+	for _, m := range matches {
+		lower, upper, isRange := strings.Cut(m[2], ",")
+		lower, upper = strings.TrimSpace(lower), strings.TrimSpace(upper)
+		if !isRange {
+			v, err := parseVersion(lower)
+			if err != nil || m[1] != "[" || m[3] != "]" {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, m[0])
+			}
+			c = append(c, []comparator{{op: "=", v: v}})
+			continue
+		}
+		var comparators []comparator
+		if lower != "" {
+			v, err := parseVersion(lower)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, m[0])
+			}
+			comparators = append(comparators, comparator{op: map[string]string{"[": ">=", "(": ">"}[m[1]], v: v})
+		}
+		if upper != "" {
+			v, err := parseVersion(upper)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, m[0])
+			}
+			comparators = append(comparators, comparator{op: map[string]string{"]": "<=", ")": "<"}[m[3]], v: v})
+		}
+		if len(comparators) == 0 {
+			comparators = append(comparators, comparator{op: ">=", v: version{release: []int{0}, min: true}})
+		}
+		c = append(c, comparators)
+	}
+	return c, nil
+}
@@ -0,0 +1,174 @@
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{
+		"0.9",
+		"1.0.dev1",
+		"1.0a1",
+		"1.0-alpha.2",
+		"1.0-beta",
+		"1.0-beta.2",
+		"1.0-beta.11",
+		"1.0rc1",
+		"1.0-SNAPSHOT",
+		"1.0",
+		"1.0.post1",
+		"1.0.1",
+		"v1.2.3",
+		"1.10",
+		"2",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			c, err := dep.CompareVersions(ordered[i], ordered[j])
+			require.NoError(t, err)
+			switch {
+			case i < j:
+				assert.Equal(t, -1, c, "%s < %s", ordered[i], ordered[j])
+			case i > j:
+				assert.Equal(t, 1, c, "%s > %s", ordered[i], ordered[j])
+			default:
+				assert.Equal(t, 0, c, "%s = %s", ordered[i], ordered[j])
+			}
+		}
+	}
+
+	equal := [][2]string{
+		{"1.0", "1.0.0"},
+		{"v1.2.3", "1.2.3"},
+		{"5.3.0.RELEASE", "5.3.0"},
+		{"1.0.0+build.5", "1.0.0"},
+	}
+	for _, pair := range equal {
+		c, err := dep.CompareVersions(pair[0], pair[1])
+		require.NoError(t, err)
+		assert.Equal(t, 0, c, "%s = %s", pair[0], pair[1])
+	}
+
+	_, err := dep.CompareVersions("dev-main", "1.0")
+	assert.ErrorIs(t, err, dep.ErrInvalidVersion)
+}
+
+func TestSatisfiesConstraint(t *testing.T) {
+	cases := []struct {
+		managerType string
+		constraint  string
+		satisfied   []string
+		unsatisfied []string
+	}{
+		// npm
+		{"js", "^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0", "2.0.0-beta.1"}},
+		{"js", "^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"js", "^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"js", "~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0"}},
+		{"js", "~1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{"js", "1.x", []string{"1.0.0", "1.99.0"}, []string{"2.0.0", "0.9.0"}},
+		{"js", "1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"js", "*", []string{"0.0.1", "10.0.0"}, nil},
+		{"js", ">=1.0.0 <2.0.0", []string{"1.0.0", "1.5.0"}, []string{"0.9.0", "2.0.0"}},
+		{"js", ">= 1.0.0 < 2.0.0", []string{"1.5.0"}, []string{"2.0.0"}},
+		{"js", "1.0.0 - 2.3", []string{"1.0.0", "2.3.9"}, []string{"2.4.0"}},
+		{"js", "^1.0.0 || ^3.0.0", []string{"1.1.0", "3.1.0"}, []string{"2.0.0"}},
+		{"js", "<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"js", ">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+
+		// Composer
+		{"php", ">=10", []string{"10.0.0", "v11.2.1"}, []string{"9.5.0"}},
+		{"php", "^10.0", []string{"10.48.2"}, []string{"11.0.0"}},
+		{"php", "~1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}},
+		{"php", "~1.2.3", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"php", "^7.4|^8.0", []string{"7.4.3", "8.3.0"}, []string{"7.3.0", "9.0.0"}},
+		{"php", ">=2.1,<3.0", []string{"2.1.0"}, []string{"3.0.0"}},
+		{"php", "1.0.*", []string{"1.0.5"}, []string{"1.1.0"}},
+		{"php", "^2.0@dev", []string{"2.1.0"}, []string{"3.0.0"}},
+
+		// PEP 440
+		{"python", "<4", []string{"3.2.25"}, []string{"4.0", "4.2"}},
+		{"python", ">=3.2,<4", []string{"3.2", "3.9.1"}, []string{"3.1", "4.0"}},
+		{"python", "~=2.2", []string{"2.2", "2.9"}, []string{"3.0", "2.1"}},
+		{"python", "~=1.4.5", []string{"1.4.5", "1.4.9"}, []string{"1.5.0"}},
+		{"python", "==1.4.*", []string{"1.4.0", "1.4.2"}, []string{"1.5.0"}},
+		{"python", "!=1.4.*", []string{"1.5.0"}, []string{"1.4.2"}},
+		{"python", "==2.0", []string{"2.0", "2.0.0"}, []string{"2.0.1"}},
+		{"python", ">=1.0,!=1.5.0", []string{"1.4.0"}, []string{"1.5.0"}},
+
+		// Maven and NuGet
+		{"java", "[1.0,2.0)", []string{"1.0", "1.9.9"}, []string{"2.0", "0.9"}},
+		{"java", "(,1.0],[1.2,)", []string{"0.5", "1.0", "1.2", "3.0"}, []string{"1.1"}},
+		{"java", "[1.5]", []string{"1.5"}, []string{"1.6"}},
+		{"java", "[6.0,)", []string{"6.1.4.RELEASE"}, []string{"5.3.0"}},
+		{"dotnet", "6.0", []string{"6.0.0", "7.0.0"}, []string{"5.0.0"}},
+
+		// RubyGems, Terraform and Mix
+		{"ruby", "~> 2.2", []string{"2.2.0", "2.9"}, []string{"3.0"}},
+		{"ruby", "~> 2.2.0", []string{"2.2.9"}, []string{"2.3.0"}},
+		{"ruby", ">= 1.0, < 3", []string{"2.5"}, []string{"3.0"}},
+		{"terraform", "~> 5.0", []string{"5.82.2"}, []string{"6.0.0", "4.67.0"}},
+		{"elixir", "~> 1.7 or ~> 2.0", []string{"1.7.1", "2.1.0"}, []string{"3.0.0"}},
+
+		// Cargo and Go
+		{"rust", "1.2", []string{"1.2.0", "1.9.0"}, []string{"2.0.0"}},
+		{"rust", "0.9", []string{"0.9.4"}, []string{"0.10.0"}},
+		{"go", ">=1.21", []string{"v1.22.0"}, []string{"v1.20.5"}},
+
+		// Other syntaxes
+		{"cpp", "[>=1.0 <2.0]", []string{"1.5"}, []string{"2.0"}},
+		{"docker", ">=3.12", []string{"3.12-slim", "3.13"}, []string{"3.11-alpine"}},
+	}
+	for _, c := range cases {
+		for _, v := range c.satisfied {
+			ok, err := dep.SatisfiesConstraint(c.managerType, v, c.constraint)
+			require.NoError(t, err)
+			assert.True(t, ok, "%s: %s should satisfy %s", c.managerType, v, c.constraint)
+		}
+		for _, v := range c.unsatisfied {
+			ok, err := dep.SatisfiesConstraint(c.managerType, v, c.constraint)
+			require.NoError(t, err)
+			assert.False(t, ok, "%s: %s should not satisfy %s", c.managerType, v, c.constraint)
+		}
+	}
+
+	_, err := dep.SatisfiesConstraint("php", "1.0.0", "dev-main")
+	assert.ErrorIs(t, err, dep.ErrInvalidConstraint)
+	_, err = dep.SatisfiesConstraint("js", "1.0.0", "")
+	assert.ErrorIs(t, err, dep.ErrInvalidConstraint)
+	_, err = dep.SatisfiesConstraint("js", "latest", "^1.0")
+	assert.ErrorIs(t, err, dep.ErrInvalidVersion)
+}
+
+func TestLowerBound(t *testing.T) {
+	cases := []struct {
+		managerType string
+		constraint  string
+		lowerBound  string
+	}{
+		{"python", "<4,>=3.11", "3.11"},
+		{"js", "<16 || >=18", "18"},
+		{"js", ">=18 <21", "18"},
+		{"js", "^20.11.0", "20.11.0"},
+		{"js", "18.x", "18"},
+		{"js", ">1.2.x", "1.3"},
+		{"php", "^7.4|^8.0", "7.4"},
+		{"ruby", "~> 3.2", "3.2"},
+		{"java", "[17,21)", "17"},
+		{"python", "<4", ""},
+		{"js", "*", ""},
+	}
+	for _, c := range cases {
+		lowerBound, err := dep.LowerBound(c.managerType, c.constraint)
+		require.NoError(t, err)
+		assert.Equal(t, c.lowerBound, lowerBound, "%s: %s", c.managerType, c.constraint)
+	}
+
+	_, err := dep.LowerBound("js", "lts/*")
+	assert.ErrorIs(t, err, dep.ErrInvalidConstraint)
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/upsun/whatsun"
+	"github.com/upsun/whatsun/pkg/dep"
 	"github.com/upsun/whatsun/pkg/eval"
+	"github.com/upsun/whatsun/pkg/license"
 	"github.com/upsun/whatsun/pkg/rules"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 type Config struct {
@@ -58,8 +62,15 @@ func NewDigester(fsys fs.FS, cnf *Config) (*Digester, error) {
 
 type Digest struct {
 	Tree          string              `json:"tree"`
-	Reports       map[string][]Report `json:"reports"` // Grouped by path
+	License       string              `json:"license,omitempty"` // The project's license, usually an SPDX identifier
+	Reports       map[string][]Report `json:"reports"`           // Grouped by path
 	SelectedFiles []FileData          `json:"selected_files"`
+
+	// The names of categorized direct dependencies, grouped by path and category (e.g. "testing").
+	Dependencies map[string]map[string][]string `json:"dependencies,omitempty"`
+
+	// Problems found in dependency files, such as syntax errors. They are not encoded, but can be shown as warnings.
+	Diagnostics []dep.Diagnostic `json:"-" yaml:"-"`
 }
 
 var defaultReadFiles = []string{
@@ -77,12 +88,15 @@ var defaultReadFiles = []string{
 }
 
 func (d *Digester) GetDigest(_ context.Context) (*Digest, error) {
-	tree, err := GetTree(d.fsys, MinimalTreeConfig)
+	// The session caches files and dependencies for the tree, the analysis, and the license and dependency summaries.
+	sess := session.New(d.fsys)
+
+	tree, err := GetTree(sess.FS(), MinimalTreeConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	reports, err := d.analyzer.Analyze(context.Background(), d.fsys, ".")
+	reports, err := d.analyzer.AnalyzeSession(context.Background(), sess, ".")
 	if err != nil {
 		return nil, err
 	}
@@ -91,18 +105,69 @@ func (d *Digester) GetDigest(_ context.Context) (*Digest, error) {
 	readFiles = append(readFiles, d.cnf.ReadFiles...)
 	readFiles = append(readFiles, customReadFiles(reports)...)
 
-	fileList, err := ReadMultiple(d.fsys, d.cnf.MaxFileLength, readFiles...)
+	fileList, err := ReadMultiple(sess.FS(), d.cnf.MaxFileLength, readFiles...)
+	if err != nil {
+		return nil, err
+	}
+
+	projectLicense, err := license.ProjectSession(sess, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies, err := categorizedDependencies(sess, reports)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Digest{
 		Tree:          strings.Join(tree, "\n"),
+		License:       projectLicense,
 		Reports:       formatReports(reports),
 		SelectedFiles: Clean(fileList),
+		Dependencies:  dependencies,
+		Diagnostics:   sess.Diagnostics(),
 	}, nil
 }
 
+// categorizedDependencies summarizes the direct dependencies in each reported path by category. Dependencies without
+// a category are left out, as are problems in dependency files.
+func categorizedDependencies(sess *session.Session, reports []rules.Report) (map[string]map[string][]string, error) {
+	result := make(map[string]map[string][]string)
+	for _, report := range reports {
+		if _, ok := result[report.Path]; ok || report.Maybe {
+			continue
+		}
+		categories := make(map[string][]string)
+		for _, managerType := range dep.ManagerTypes() {
+			m, err := sess.Manager(managerType, report.Path)
+			if err != nil {
+				return nil, err
+			}
+			deps := m.Find("*")
+			dep.Categorize(managerType, deps)
+			for _, d := range deps {
+				if d.IsDirect && d.Category != "" && !slices.Contains(categories[d.Category], d.Name) {
+					categories[d.Category] = append(categories[d.Category], d.Name)
+				}
+			}
+		}
+		for _, names := range categories {
+			slices.Sort(names)
+		}
+		result[report.Path] = categories
+	}
+	for path, categories := range result {
+		if len(categories) == 0 {
+			delete(result, path)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
 // customReadFiles returns project-specific files that should be read (as glob patterns).
 func customReadFiles(reports []rules.Report) []string {
 	var readFiles []string
@@ -22,12 +22,14 @@ var digestTestCases = []digestTestCase{
 	{
 		name: "symfony basic",
 		fsys: fstest.MapFS{
+			`LICENSE`:       &fstest.MapFile{Data: []byte("SPDX-License-Identifier: MIT\n")},
 			`composer.json`: &fstest.MapFile{Data: []byte(`{"require": {"symfony/framework-bundle": "^7", "php": "^8.3"}}`)},
 			`composer.lock`: &fstest.MapFile{Data: []byte(
 				`{"packages": [{"name": "symfony/framework-bundle", "version": "7.2.3"}]}`)},
 		},
 		expected: &digest.Digest{
-			Tree: ".\n  composer.json\n  composer.lock",
+			Tree:    ".\n  LICENSE\n  composer.json\n  composer.lock",
+			License: "MIT",
 			Reports: map[string][]digest.Report{
 				".": {
 					{Result: "symfony", Ruleset: "frameworks", Groups: []string{"php", "symfony"}, With: map[string]any{
@@ -36,11 +38,15 @@ var digestTestCases = []digestTestCase{
 					{Result: "composer", Ruleset: "package_managers", Groups: []string{"php"}, With: map[string]any{
 						"php_version": "^8.3",
 					}},
+					{Result: "php", Ruleset: "runtimes", With: map[string]any{"version": "8.3"}},
 				},
 			},
 			SelectedFiles: []digest.FileData{
 				{Name: "composer.json", Content: `{"require": {"symfony/framework-bundle": "^7", "php": "^8.3"}}`, Size: 62},
 			},
+			Dependencies: map[string]map[string][]string{
+				".": {"framework": {"symfony/framework-bundle"}},
+			},
 		},
 	},
 	{
@@ -116,6 +122,7 @@ var digestTestCases = []digestTestCase{
 						With: map[string]any{"version": "7.2.3"}},
 					{Result: "composer", Ruleset: "package_managers", Groups: []string{"php"},
 						With: map[string]any{"php_version": "^8.3"}},
+					{Result: "php", Ruleset: "runtimes", With: map[string]any{"version": "8.3"}},
 				},
 				"ambiguous": {{Result: "gatsby", Ruleset: "frameworks", Groups: []string{"js"},
 					With: map[string]any{}}},
@@ -141,6 +148,10 @@ var digestTestCases = []digestTestCase{
 				{Name: "deep/1/2/3/4/5/composer.json", Content: "{}", Size: 2},
 				{Name: "deep/a/b/c/d/e/package.json", Content: "{}", Size: 2},
 			},
+			Dependencies: map[string]map[string][]string{
+				".":         {"framework": {"symfony/framework-bundle"}},
+				"ambiguous": {"framework": {"gatsby"}},
+			},
 		},
 	},
 }
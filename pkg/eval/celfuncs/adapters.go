@@ -6,6 +6,22 @@ import (
 	"github.com/google/cel-go/common/types/ref"
 )
 
+// unaryFunction makes a CEL environment option for a function that takes 1 argument.
+func unaryFunction[ARG any, R any](name string, argType, returnType *cel.Type, f func(ARG) (R, error)) cel.EnvOption {
+	return cel.Function(
+		name,
+		cel.Overload(name, []*cel.Type{argType}, returnType,
+			cel.UnaryBinding(func(arg ref.Val) ref.Val {
+				res, err := f(arg.Value().(ARG)) //nolint:errcheck
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.DefaultTypeAdapter.NativeToValue(res)
+			}),
+		),
+	)
+}
+
 // binaryFunction makes a CEL environment option for a function that takes 2 arguments.
 func binaryFunction[ARG1 any, ARG2 any, R any](name string, argTypes []*cel.Type, returnType *cel.Type,
 	f func(ARG1, ARG2) (R, error)) cel.EnvOption {
@@ -66,3 +82,27 @@ func binaryReceiverFunction[REC any, ARG1 any, ARG2 any, RET any](
 		),
 	)
 }
+
+// ternaryReceiverFunction makes a CEL environment option for a function that takes a receiver and 3 other arguments.
+func ternaryReceiverFunction[REC any, ARG1 any, ARG2 any, ARG3 any, RET any](
+	receiverName, functionName string,
+	argTypes []*cel.Type,
+	returnType *cel.Type,
+	f func(REC, ARG1, ARG2, ARG3) (RET, error),
+) cel.EnvOption {
+	overloadID := receiverName + "." + functionName
+
+	return cel.Function(functionName,
+		cel.MemberOverload(overloadID, argTypes, returnType,
+			cel.FunctionBinding(func(args ...ref.Val) ref.Val {
+				//nolint:errcheck
+				res, err := f(args[0].Value().(REC), args[1].Value().(ARG1),
+					args[2].Value().(ARG2), args[3].Value().(ARG3))
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				return types.DefaultTypeAdapter.NativeToValue(res)
+			}),
+		),
+	)
+}
@@ -20,5 +20,10 @@ func CustomEnvOptions(docs *Docs) []cel.EnvOption {
 	return append(celOptions,
 		JQ(docs),
 		YQ(docs),
+		TOML(docs),
+		RegexFind(docs),
+		SemverCompare(docs),
+		NormalizeVersion(docs),
+		Copyleft(docs),
 	)
 }
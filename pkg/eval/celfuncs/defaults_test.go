@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/upsun/whatsun/pkg/eval/celfuncs"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 func TestCEL(t *testing.T) {
@@ -22,6 +23,18 @@ func TestCEL(t *testing.T) {
 		"package.json": &fstest.MapFile{Data: []byte(
 			`{"name": "test", "dependencies": {"express": "github:expressjs/express"}}`)},
 		"bun.lock": &fstest.MapFile{Data: []byte(`{"packages": {"express": ["express@1.0.0"]}}`)},
+		"php/composer.json": &fstest.MapFile{Data: []byte(
+			`{"require": {"php": ">=8.2", "ext-redis": "*", "drupal/core": "^11"}}`)},
+		"php/composer.lock": &fstest.MapFile{Data: []byte(`{"packages": [
+			{"name": "drupal/core", "version": "11.1.0", "license": ["GPL-2.0-or-later"]},
+			{"name": "symfony/console", "version": "v7.2.1", "license": ["MIT"]},
+			{"name": "pear/archive_tar", "version": "1.5.0", "license": ["BSD-2-Clause"]},
+			{"name": "tecnickcom/tcpdf", "version": "6.8.0", "license": ["LGPL-3.0-or-later"]}
+		]}`)},
+		"tf/main.tf": &fstest.MapFile{Data: []byte(`module "a" { source = "acme/a/aws" }
+module "b" { source = "git::https://example.com/b.git" }`)},
+		"broken/composer.json": &fstest.MapFile{Data: []byte(`{"require": {"a/b": "^1"}}`)},
+		"broken/composer.lock": &fstest.MapFile{Data: []byte("{\n  \"packages\": [{\"name\": \"a/b\",}]\n}")},
 	}
 
 	env, err := cel.NewEnv(celfuncs.DefaultEnvOptions()...)
@@ -56,18 +69,67 @@ func TestCEL(t *testing.T) {
 		{expr: `fs.fileContains("foo.txt", "foo")`, path: ".", expectResult: true},
 		{expr: `fs.fileContains("foo.txt", "subdir")`, path: ".", expectResult: false},
 		{expr: `fs.fileContains("foo.txt", "subdir")`, path: "subdir", expectResult: true},
+		{expr: `fs.globContains("*.txt", "foo")`, path: ".", expectResult: true},
+		{expr: `fs.globContains("*/*.txt", "bar")`, path: ".", expectResult: true},
+		{expr: `fs.globContains("*.txt", "subdir")`, path: "subdir", expectResult: true},
+		{expr: `fs.globContains("*.txt", "bar")`, path: ".", expectResult: false},
+		{expr: `fs.globContains("sub*", "bar")`, path: ".", expectResult: false},
 		{expr: `jq(fs.read("package.json"), ".name")`, path: ".", expectResult: "test"},
 		{expr: `jq(fs.read("invalid.json"), ".test")`, path: ".", expectEvalErrContains: "invalid character"},
 		{expr: `fs.depExists("js", "express")`, path: ".", expectResult: true},
 		{expr: `fs.depVersion("js", "express")`, path: ".", expectResult: "1.0.0"},
 		{expr: `fs.depExists("js", "nextjs")`, path: ".", expectResult: false},
-		{expr: `fs.depExists("swift", "test")`, path: ".", expectEvalErrContains: "manager type not supported"},
+		{expr: `fs.depExists("cobol", "test")`, path: ".", expectEvalErrContains: "manager type not supported"},
+		{expr: `fs.depSatisfies("js", "express", ">=1")`, path: ".", expectResult: true},
+		{expr: `fs.depSatisfies("js", "express", "^2.0")`, path: ".", expectResult: false},
+		{expr: `fs.depSatisfies("js", "nextjs", "*")`, path: ".", expectResult: false},
+		{expr: `fs.depSatisfies("js", "express", "")`, path: ".", expectEvalErrContains: "invalid version constraint"},
+		{expr: `semverCompare("1.2.0", "1.10.0")`, path: ".", expectResult: -1},
+		{expr: `semverCompare("v2.0.0", "2.0")`, path: ".", expectResult: 0},
+		{expr: `semverCompare("2.0.0", "2.0.0-rc1")`, path: ".", expectResult: 1},
+		{expr: `normalizeVersion("v20.11.0")`, path: ".", expectResult: "20.11.0"},
+		{expr: `normalizeVersion("go1.22.3")`, path: ".", expectResult: "1.22.3"},
+		{expr: `normalizeVersion(">=3.11,<4")`, path: ".", expectResult: "3.11"},
+		{expr: `normalizeVersion("<4,>=3.11")`, path: ".", expectResult: "3.11"},
+		{expr: `normalizeVersion("<21 || >=18")`, path: ".", expectResult: "18"},
+		{expr: `normalizeVersion("^7.4|^8.0")`, path: ".", expectResult: "7.4"},
+		{expr: `normalizeVersion("~> 3.2.0")`, path: ".", expectResult: "3.2.0"},
+		{expr: `normalizeVersion("<4")`, path: ".", expectResult: ""},
+		{expr: `normalizeVersion("3.12.1\n")`, path: ".", expectResult: "3.12.1"},
+		{expr: `normalizeVersion("temurin-21.0.1\n")`, path: ".", expectResult: "21.0.1"},
+		{expr: `normalizeVersion("18.x")`, path: ".", expectResult: "18"},
+		{expr: `normalizeVersion("nightly-2024-01-01")`, path: ".", expectResult: ""},
+		{expr: `normalizeVersion("lts/*")`, path: ".", expectResult: ""},
+		{expr: `toml(b"[toolchain]\nchannel = \"1.75\"", ".toolchain.channel")`, path: ".", expectResult: "1.75"},
+		{expr: `toml(b"[project]\nversion = 2", ".project.version")`, path: ".", expectResult: "2"},
+		{expr: `toml(b"[project", ".project")`, path: ".", expectEvalErrContains: "toml"},
+		{expr: `regexFind(b"module a\n\ngo 1.22\n", r"(?m)^go\s+(\S+)")`, path: ".", expectResult: "1.22"},
+		{expr: `regexFind(b"<java.version>17</java.version>", r"\d+")`, path: ".", expectResult: "17"},
+		{expr: `regexFind(fs.read("foo.txt"), "bar")`, path: ".", expectResult: ""},
+		{expr: `regexFind(b"", "(")`, path: ".", expectEvalErrContains: "missing closing )"},
+		{expr: `semverCompare("main", "1.0")`, path: ".", expectEvalErrContains: "invalid version"},
+		{expr: `fs.depLicense("php", "symfony/console")`, path: "php", expectResult: "MIT"},
+		{expr: `fs.depExists("php", "ext-redis")`, path: "php", expectResult: false},
+		{expr: `fs.platformExists("php", "ext-redis")`, path: "php", expectResult: true},
+		{expr: `fs.platformExists("php", "ext-*")`, path: "php", expectResult: true},
+		{expr: `fs.platformExists("php", "ext-pgsql")`, path: "php", expectResult: false},
+		{expr: `fs.platformExists("js", "node")`, path: ".", expectResult: false},
+		{expr: `fs.depExists("php", "a/b")`, path: "broken", expectResult: true},
+		{expr: `fs.depVersion("php", "a/b")`, path: "broken", expectResult: ""},
+		{expr: `fs.depLicense("php", "nonexistent/package")`, path: "php", expectResult: ""},
+		{expr: `fs.copyleftDeps("php") == ["drupal/core", "tecnickcom/tcpdf"]`, path: "php", expectResult: true},
+		{expr: `fs.copyleftDeps("js").size()`, path: ".", expectResult: 0},
+		{expr: `fs.registryDeps("terraform") == ["acme/a/aws"]`, path: "tf", expectResult: true},
+		{expr: `fs.registryDeps("js").size()`, path: ".", expectResult: 0},
+		{expr: `copyleft("GPL-3.0-or-later")`, path: ".", expectResult: "strong"},
+		{expr: `copyleft("MIT OR LGPL-2.1-only")`, path: ".", expectResult: ""},
 	}
 
+	sess := session.New(fsys)
 	for _, tc := range testCases {
 		msgAndArgs := []any{"expr %s, path %s", tc.expr, tc.path}
 
-		input := celfuncs.FilesystemInput(fsys, tc.path)
+		input := celfuncs.SessionInput(sess, tc.path)
 
 		ast, iss := env.Compile(tc.expr)
 		if tc.expectCompileErr {
@@ -9,6 +9,7 @@ import (
 	"github.com/google/cel-go/cel"
 
 	"github.com/upsun/whatsun/internal/fsdir"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 const (
@@ -17,15 +18,24 @@ const (
 )
 
 // FilesystemInput returns a CEL program input for a filesystem named "fs", and a file path variable named "path".
+// Each call starts a new session, so files and dependencies are not cached between calls: use SessionInput to share
+// a session between evaluations.
 // This can only be used alongside the FilesystemVariables options.
 func FilesystemInput(fsys fs.FS, path string) map[string]any {
+	return SessionInput(session.New(fsys), path)
+}
+
+// SessionInput returns a CEL program input for the filesystem of a session named "fs", and a file path variable
+// named "path". Functions reading files and dependencies use the caches of the session.
+// This can only be used alongside the FilesystemVariables options.
+func SessionInput(s *session.Session, path string) map[string]any {
 	return map[string]any{
 		pathVariable: path,
-		fsVariable:   fsdir.New(fsys, path),
+		fsVariable:   fsdir.New(s, path),
 	}
 }
 
-// FilesystemVariables returns CEL options to create variables corresponding to FilesystemInput.
+// FilesystemVariables returns CEL options to create variables corresponding to FilesystemInput or SessionInput.
 func FilesystemVariables() []cel.EnvOption {
 	return []cel.EnvOption{
 		cel.Variable(pathVariable, cel.StringType),
@@ -40,6 +50,7 @@ func AllFileOptions(docs *Docs) []cel.EnvOption {
 		FileContains(docs),
 		FileExists(docs),
 		FileGlob(docs),
+		FileGlobContains(docs),
 		FileIsDir(docs),
 		FileRead(docs),
 	}
@@ -96,6 +107,39 @@ func FileGlob(docs *Docs) cel.EnvOption {
 	)
 }
 
+func FileGlobContains(docs *Docs) cel.EnvOption {
+	docs.AddFunction("globContains", FuncDoc{
+		Comment: "Check whether any file matching a glob pattern contains a substring",
+		Args: []ArgDoc{
+			{"fs", ""},
+			{"pattern", ""},
+			{"substr", ""},
+		},
+	})
+
+	return fsBinaryFunction("globContains", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+		func(fsd fsdir.FSDir, pattern, substr string) (bool, error) {
+			matches, err := fs.Glob(fsd.FS(), filepath.Join(fsd.Path(), pattern))
+			if err != nil {
+				return false, err
+			}
+			for _, name := range matches {
+				if stat, err := fs.Stat(fsd.FS(), name); err != nil || stat.IsDir() {
+					continue
+				}
+				b, err := fs.ReadFile(fsd.FS(), name)
+				if err != nil {
+					return false, err
+				}
+				if strings.Contains(string(b), substr) {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	)
+}
+
 func FileRead(docs *Docs) cel.EnvOption {
 	docs.AddFunction("read", FuncDoc{
 		Comment: "Read a file",
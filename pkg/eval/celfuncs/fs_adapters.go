@@ -17,3 +17,9 @@ func fsBinaryFunction[A1 any, A2 any, RET any](name string, argTypes []*cel.Type
 	f func(fsdir.FSDir, A1, A2) (RET, error)) cel.EnvOption {
 	return binaryReceiverFunction(fsVariable, name, append([]*cel.Type{cel.DynType}, argTypes...), returnType, f)
 }
+
+// fsTernaryFunction returns a CEL environment option for a receiver method on the "fs" variable that takes 3 arguments.
+func fsTernaryFunction[A1 any, A2 any, A3 any, RET any](name string, argTypes []*cel.Type, returnType *cel.Type,
+	f func(fsdir.FSDir, A1, A2, A3) (RET, error)) cel.EnvOption {
+	return ternaryReceiverFunction(fsVariable, name, append([]*cel.Type{cel.DynType}, argTypes...), returnType, f)
+}
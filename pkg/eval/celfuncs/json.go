@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/BurntSushi/toml"
 	"github.com/google/cel-go/cel"
 	"github.com/itchyny/gojq"
 	"gopkg.in/yaml.v3"
@@ -50,6 +51,35 @@ func YQ(docs *Docs) cel.EnvOption {
 	)
 }
 
+func TOML(docs *Docs) cel.EnvOption {
+	docs.AddFunction("toml", FuncDoc{
+		Comment: "Query TOML bytes (e.g. file contents) using JQ syntax",
+		Args: []ArgDoc{
+			{"contents", ""},
+			{"query", ""},
+		},
+	})
+
+	return binaryFunction("toml", []*cel.Type{cel.BytesType, cel.StringType}, cel.StringType,
+		func(b []byte, expr string) (string, error) {
+			m := map[string]any{}
+			if err := toml.Unmarshal(b, &m); err != nil {
+				return "", err
+			}
+			// Convert TOML types (e.g. int64 or dates) to the JSON types supported by JQ.
+			j, err := json.Marshal(m)
+			if err != nil {
+				return "", err
+			}
+			m = map[string]any{}
+			if err := json.Unmarshal(j, &m); err != nil {
+				return "", err
+			}
+			return jq(m, expr)
+		},
+	)
+}
+
 func jq(m map[string]any, expr string) (string, error) {
 	query, err := gojq.Parse(expr)
 	if err != nil {
@@ -0,0 +1,23 @@
+package celfuncs
+
+import (
+	"github.com/google/cel-go/cel"
+
+	"github.com/upsun/whatsun/pkg/license"
+)
+
+func Copyleft(docs *Docs) cel.EnvOption {
+	docs.AddFunction("copyleft", FuncDoc{
+		Comment: "Find the copyleft level of a license",
+		Description: "This returns `strong` (e.g. for GPL or AGPL licenses), `weak` (e.g. for LGPL, MPL or EPL " +
+			"licenses), or an empty string for permissive or unknown licenses. In an SPDX expression, the least " +
+			"restrictive alternative of `OR` is used, and the most restrictive part of `AND`.",
+		Args: []ArgDoc{
+			{"expression", "The license, as an SPDX expression (e.g. `MIT OR GPL-3.0-or-later`)"},
+		},
+	})
+
+	return unaryFunction("copyleft", cel.StringType, cel.StringType, func(expression string) (string, error) {
+		return license.Copyleft(expression), nil
+	})
+}
@@ -1,13 +1,17 @@
 package celfuncs
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/IGLOU-EU/go-wildcard/v2"
 	"github.com/google/cel-go/cel"
 
 	"github.com/upsun/whatsun/internal/fsdir"
 	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/license"
 )
 
 // AllPackageManagerFunctions returns CEL functions for reading package manager dependencies in an fs.FS filesystem.
@@ -15,12 +19,24 @@ import (
 func AllPackageManagerFunctions(docs *Docs) []cel.EnvOption {
 	return []cel.EnvOption{
 		DepExists(docs),
+		PlatformExists(docs),
 		DepVersion(docs),
+		DepSatisfies(docs),
+		DepLicense(docs),
+		CopyleftDeps(docs),
+		RegistryDeps(docs),
 	}
 }
 
 func managerTypeComment() string {
-	return fmt.Sprintf("The manager type (one of: `%s`)", strings.Join(dep.AllManagerTypes, "`, `"))
+	return fmt.Sprintf("The manager type (one of: `%s`)", strings.Join(dep.ManagerTypes(), "`, `"))
+}
+
+// getManager returns the initialized dependency manager for a directory, cached in the session. Problems found in its
+// files, such as syntax errors, are not returned as errors: the manager still has the dependencies from the files that
+// could be read, and the problems are listed by the session's Diagnostics method.
+func getManager(managerType string, fsd fsdir.FSDir) (dep.Manager, error) {
+	return fsd.Session().Manager(managerType, fsd.Path())
 }
 
 func DepExists(docs *Docs) cel.EnvOption {
@@ -36,7 +52,7 @@ func DepExists(docs *Docs) cel.EnvOption {
 
 	return fsBinaryFunction("depExists", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
 		func(fsd fsdir.FSDir, managerType string, pattern string) (bool, error) {
-			m, err := dep.GetCachedManager(managerType, fsd)
+			m, err := getManager(managerType, fsd)
 			if err != nil {
 				return false, err
 			}
@@ -45,6 +61,36 @@ func DepExists(docs *Docs) cel.EnvOption {
 	)
 }
 
+func PlatformExists(docs *Docs) cel.EnvOption {
+	docs.AddFunction("platformExists", FuncDoc{
+		Comment: "Check if a project has a platform requirement",
+		Description: "Platform requirements are declared separately from dependencies, e.g. PHP extensions such as " +
+			"`ext-redis` in `composer.json`, or the Ruby version in a `Gemfile`. " +
+			"This returns false if the manager type does not support platform requirements.",
+		Args: []ArgDoc{
+			{"fs", "The filesystem, representing each directory"},
+			{"managerType", managerTypeComment()},
+			{"pattern", "The requirement name, accepting `*` as a wildcard"},
+		},
+	})
+
+	return fsBinaryFunction("platformExists", []*cel.Type{cel.StringType, cel.StringType}, cel.BoolType,
+		func(fsd fsdir.FSDir, managerType string, pattern string) (bool, error) {
+			m, err := getManager(managerType, fsd)
+			if err != nil {
+				return false, err
+			}
+			pm, ok := m.(dep.PlatformManager)
+			if !ok {
+				return false, nil
+			}
+			return slices.ContainsFunc(pm.Platform(), func(d dep.Dependency) bool {
+				return wildcard.Match(pattern, d.Name)
+			}), nil
+		},
+	)
+}
+
 func DepVersion(docs *Docs) cel.EnvOption {
 	docs.AddFunction("depVersion", FuncDoc{
 		Comment:     "Find the version of a project dependency",
@@ -58,7 +104,7 @@ func DepVersion(docs *Docs) cel.EnvOption {
 
 	return fsBinaryFunction("depVersion", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType,
 		func(fsd fsdir.FSDir, managerType string, name string) (string, error) {
-			m, err := dep.GetCachedManager(managerType, fsd)
+			m, err := getManager(managerType, fsd)
 			if err != nil {
 				return "", err
 			}
@@ -67,3 +113,120 @@ func DepVersion(docs *Docs) cel.EnvOption {
 		},
 	)
 }
+
+func DepSatisfies(docs *Docs) cel.EnvOption {
+	docs.AddFunction("depSatisfies", FuncDoc{
+		Comment: "Check if the installed version of a project dependency satisfies a constraint",
+		Description: "The constraint uses the syntax of the package manager, e.g. `^10.0` for Composer, " +
+			"`>=3.2,<4` for Python, or `[1.0,2.0)` for Maven. Comparison operators such as `>=10` work everywhere. " +
+			"This returns false if the dependency is not found, or if its installed version is unknown or invalid.",
+		Args: []ArgDoc{
+			{"fs", "The filesystem, representing each directory"},
+			{"managerType", managerTypeComment()},
+			{"name", "The dependency name"},
+			{"constraint", "The version constraint"},
+		},
+	})
+
+	return fsTernaryFunction("depSatisfies", []*cel.Type{cel.StringType, cel.StringType, cel.StringType},
+		cel.BoolType,
+		func(fsd fsdir.FSDir, managerType, name, constraint string) (bool, error) {
+			m, err := getManager(managerType, fsd)
+			if err != nil {
+				return false, err
+			}
+			d, ok := m.Get(name)
+			if !ok || d.Version == "" {
+				return false, nil
+			}
+			satisfied, err := dep.SatisfiesConstraint(managerType, d.Version, constraint)
+			if errors.Is(err, dep.ErrInvalidVersion) {
+				return false, nil
+			}
+			return satisfied, err
+		},
+	)
+}
+
+func DepLicense(docs *Docs) cel.EnvOption {
+	docs.AddFunction("depLicense", FuncDoc{
+		Comment: "Find the declared license of a project dependency",
+		Description: "Licenses are read from lock files, currently `composer.lock` and `package-lock.json`. " +
+			"This returns an empty string if the dependency or its license is not found.",
+		Args: []ArgDoc{
+			{"fs", "The filesystem, representing each directory"},
+			{"managerType", managerTypeComment()},
+			{"name", "The dependency name"},
+		},
+	})
+
+	return fsBinaryFunction("depLicense", []*cel.Type{cel.StringType, cel.StringType}, cel.StringType,
+		func(fsd fsdir.FSDir, managerType string, name string) (string, error) {
+			m, err := getManager(managerType, fsd)
+			if err != nil {
+				return "", err
+			}
+			d, _ := m.Get(name)
+			return d.License, nil
+		},
+	)
+}
+
+func CopyleftDeps(docs *Docs) cel.EnvOption {
+	docs.AddFunction("copyleftDeps", FuncDoc{
+		Comment: "List the project dependencies that have a copyleft license",
+		Description: "This returns the sorted names of dependencies with a strong or weak copyleft license " +
+			"(see `copyleft`), including indirect dependencies. Dependencies with unknown licenses are not included.",
+		Args: []ArgDoc{
+			{"fs", "The filesystem, representing each directory"},
+			{"managerType", managerTypeComment()},
+		},
+	})
+
+	return fsUnaryFunction("copyleftDeps", cel.StringType, cel.ListType(cel.StringType),
+		func(fsd fsdir.FSDir, managerType string) ([]string, error) {
+			m, err := getManager(managerType, fsd)
+			if err != nil {
+				return nil, err
+			}
+			names := []string{}
+			for _, d := range m.Find("*") {
+				if d.License != "" && license.Copyleft(d.License) != license.CopyleftNone {
+					names = append(names, d.Name)
+				}
+			}
+			slices.Sort(names)
+			return names, nil
+		},
+	)
+}
+
+func RegistryDeps(docs *Docs) cel.EnvOption {
+	docs.AddFunction("registryDeps", FuncDoc{
+		Comment: "List the project dependencies that are installed from a package registry",
+		Description: "This returns the sorted names of dependencies with a known registry, e.g. Terraform registry " +
+			"modules, or packages with a registry URL in a lock file. Dependencies from other sources, such as Git " +
+			"repositories or local paths, are not included.",
+		Args: []ArgDoc{
+			{"fs", "The filesystem, representing each directory"},
+			{"managerType", managerTypeComment()},
+		},
+	})
+
+	return fsUnaryFunction("registryDeps", cel.StringType, cel.ListType(cel.StringType),
+		func(fsd fsdir.FSDir, managerType string) ([]string, error) {
+			m, err := getManager(managerType, fsd)
+			if err != nil {
+				return nil, err
+			}
+			names := []string{}
+			for _, d := range m.Find("*") {
+				if d.Registry != "" {
+					names = append(names, d.Name)
+				}
+			}
+			slices.Sort(names)
+			return names, nil
+		},
+	)
+}
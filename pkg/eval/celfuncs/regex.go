@@ -0,0 +1,38 @@
+package celfuncs
+
+import (
+	"regexp"
+
+	"github.com/google/cel-go/cel"
+)
+
+func RegexFind(docs *Docs) cel.EnvOption {
+	docs.AddFunction("regexFind", FuncDoc{
+		Comment: "Find a match for a regular expression in bytes (e.g. file contents)",
+		Description: "This returns the first capturing group of the first match, or the whole match if the " +
+			"expression has no groups, or an empty string if there is no match. The expression uses Go's RE2 " +
+			"syntax, e.g. `(?m)^go\\s+(\\S+)` to find the `go` directive in a `go.mod` file.",
+		Args: []ArgDoc{
+			{"contents", ""},
+			{"pattern", "The regular expression"},
+		},
+	})
+
+	return binaryFunction("regexFind", []*cel.Type{cel.BytesType, cel.StringType}, cel.StringType,
+		func(b []byte, pattern string) (string, error) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return "", err
+			}
+			m := re.FindSubmatch(b)
+			switch {
+			case m == nil:
+				return "", nil
+			case len(m) > 1:
+				return string(m[1]), nil
+			default:
+				return string(m[0]), nil
+			}
+		},
+	)
+}
@@ -0,0 +1,59 @@
+package celfuncs
+
+import (
+	"regexp"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+func SemverCompare(docs *Docs) cel.EnvOption {
+	docs.AddFunction("semverCompare", FuncDoc{
+		Comment: "Compare two versions",
+		Description: "This returns -1 if the first version is lower, 0 if they are equal, or 1 if it is greater. " +
+			"Pre-release versions (e.g. `1.0-beta`) are lower than releases. An error is returned for invalid versions.",
+		Args: []ArgDoc{
+			{"a", "The first version"},
+			{"b", "The second version"},
+		},
+	})
+
+	return binaryFunction("semverCompare", []*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+		func(a, b string) (int, error) {
+			return dep.CompareVersions(a, b)
+		},
+	)
+}
+
+func NormalizeVersion(docs *Docs) cel.EnvOption {
+	docs.AddFunction("normalizeVersion", FuncDoc{
+		Comment: "Extract a plain version number from a version string or constraint",
+		Description: "This returns the version without prefixes or operators, e.g. `20.11.0` for `v20.11.0`, " +
+			"or `1.22.3` for `go1.22.3`. For a constraint, it returns the lower bound, e.g. `3.11` for `<4,>=3.11`, " +
+			"or `18` for `<16 || >=18`. Otherwise, the first version number is found, e.g. `21.0.1` for " +
+			"`temurin-21.0.1`. Dates (e.g. in `nightly-2024-01-01`) are not versions. An empty string is returned if " +
+			"no version is found, e.g. for `lts/*`, `stable` or `<4`.",
+		Args: []ArgDoc{
+			{"version", "The version string or constraint"},
+		},
+	})
+
+	return unaryFunction("normalizeVersion", cel.StringType, cel.StringType, func(s string) (string, error) {
+		return normalizeVersion(s), nil
+	})
+}
+
+var (
+	versionNumberPattern = regexp.MustCompile(`\d+(?:\.\d+)*`)
+	datePattern          = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+)
+
+// normalizeVersion returns the lower bound of a constraint, or else the first version number in a string, such as
+// the content of a version file.
+func normalizeVersion(s string) string {
+	if v, err := dep.LowerBound("", s); err == nil {
+		return v
+	}
+	return versionNumberPattern.FindString(datePattern.ReplaceAllString(s, ""))
+}
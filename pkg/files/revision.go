@@ -0,0 +1,198 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/upsun/whatsun/pkg/searchfs"
+)
+
+// RevisionFS returns a read-only filesystem of the files in a revision (e.g. a branch, tag or commit hash) of a local
+// Git repository. The dir may be any directory inside the repository's work tree, which becomes the root of the
+// filesystem.
+//
+// Only regular files and directories are included: symbolic links and submodules are not.
+func RevisionFS(dir, revision string) (fs.FS, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Git repository: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision %q: %w", revision, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of commit %s: %w", hash, err)
+	}
+
+	var fsys fs.FS = &treeFS{root: tree, modTime: commit.Committer.When}
+
+	// Use the subdirectory of the work tree, if dir is one.
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(wt.Filesystem.Root(), abs)
+	if err != nil {
+		return nil, err
+	}
+	if rel = filepath.ToSlash(rel); rel != "." {
+		if fsys, err = fs.Sub(fsys, rel); err != nil {
+			return nil, err
+		}
+	}
+
+	return searchfs.New(fsys), nil
+}
+
+// treeFS implements fs.FS for a Git tree. The contents of files are read when they are opened.
+type treeFS struct {
+	mux     sync.Mutex // The tree caches subtrees, so it is not safe for concurrent use.
+	root    *object.Tree
+	modTime time.Time
+}
+
+func (t *treeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if name == "." {
+		return t.openDir(name, t.root)
+	}
+	entry, err := t.root.FindEntry(name)
+	if err != nil {
+		if errors.Is(err, object.ErrEntryNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	switch entry.Mode {
+	case filemode.Dir:
+		tree, err := t.root.Tree(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return t.openDir(name, tree)
+	case filemode.Regular, filemode.Deprecated, filemode.Executable:
+		f, err := t.root.TreeEntryFile(entry)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		r, err := f.Reader()
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+		}
+		info := &treeFileInfo{name: path.Base(name), size: int64(len(b)), mode: 0o444, modTime: t.modTime}
+		return &treeFile{Reader: bytes.NewReader(b), info: info}, nil
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+}
+
+// openDir opens a directory, reading the information of its entries.
+func (t *treeFS) openDir(name string, tree *object.Tree) (fs.File, error) {
+	var entries []fs.DirEntry
+	for _, e := range tree.Entries {
+		info := &treeFileInfo{name: e.Name, modTime: t.modTime}
+		switch e.Mode {
+		case filemode.Dir:
+			info.mode = fs.ModeDir | 0o555
+		case filemode.Regular, filemode.Deprecated, filemode.Executable:
+			info.mode = 0o444
+			size, err := tree.Size(e.Name)
+			if err != nil {
+				return nil, &fs.PathError{Op: "readdir", Path: path.Join(name, e.Name), Err: err}
+			}
+			info.size = size
+		default:
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	info := &treeFileInfo{name: path.Base(name), mode: fs.ModeDir | 0o555, modTime: t.modTime}
+	return &treeDir{info: info, entries: entries}, nil
+}
+
+type treeFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i *treeFileInfo) Name() string       { return i.name }
+func (i *treeFileInfo) Size() int64        { return i.size }
+func (i *treeFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i *treeFileInfo) ModTime() time.Time { return i.modTime }
+func (i *treeFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *treeFileInfo) Sys() any           { return nil }
+
+type treeFile struct {
+	*bytes.Reader
+	info *treeFileInfo
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Close() error               { return nil }
+
+type treeDir struct {
+	info    *treeFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.offset += n
+	return remaining[:n], nil
+}
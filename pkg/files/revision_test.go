@@ -0,0 +1,71 @@
+package files_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/files"
+)
+
+func TestRevisionFS(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(files map[string]string) {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		_, err := wt.Commit("Update", &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+	commit(map[string]string{
+		"composer.json":         `{"require": {"a/a": "^1"}}`,
+		"apps/web/package.json": `{"dependencies": {"react": "^18"}}`,
+	})
+	_, err = repo.CreateTag("v1", mustHead(t, repo), nil)
+	require.NoError(t, err)
+	commit(map[string]string{
+		"composer.json":  `{"require": {"a/a": "^2"}}`,
+		"apps/README.md": "Apps",
+	})
+
+	fsys, err := files.RevisionFS(dir, "v1")
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(fsys, "composer.json", "apps/web/package.json"))
+	b, err := fs.ReadFile(fsys, "composer.json")
+	require.NoError(t, err)
+	assert.Equal(t, `{"require": {"a/a": "^1"}}`, string(b))
+	_, err = fs.Stat(fsys, "apps/README.md")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	fsys, err = files.RevisionFS(filepath.Join(dir, "apps"), "HEAD")
+	require.NoError(t, err)
+	require.NoError(t, fstest.TestFS(fsys, "README.md", "web/package.json"))
+
+	_, err = files.RevisionFS(dir, "nonexistent")
+	assert.Error(t, err)
+}
+
+func mustHead(t *testing.T, repo *git.Repository) plumbing.Hash {
+	head, err := repo.Head()
+	require.NoError(t, err)
+	return head.Hash()
+}
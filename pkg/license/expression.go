@@ -0,0 +1,176 @@
+package license
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Copyleft levels.
+const (
+	CopyleftNone   = ""
+	CopyleftWeak   = "weak"   // Changes to the licensed files must be shared, e.g. LGPL, MPL or EPL.
+	CopyleftStrong = "strong" // Derived works must be shared under the same license, e.g. GPL or AGPL.
+)
+
+// ErrInvalidExpression is returned for an invalid SPDX license expression.
+var ErrInvalidExpression = errors.New("invalid license expression")
+
+// strongCopyleftPrefixes and weakCopyleftPrefixes identify license families, by the prefix of their (upper case)
+// SPDX identifiers, or of common non-standard names such as "GPLv3".
+var (
+	strongCopyleftPrefixes = []string{"GPL", "AGPL", "SSPL", "OSL-", "EUPL-", "CC-BY-SA-", "CC-BY-NC-SA-", "RPL-"}
+	weakCopyleftPrefixes   = []string{"LGPL", "MPL-", "EPL-", "CDDL-", "CPL-", "MS-RL", "APSL-"}
+)
+
+// Copyleft returns the copyleft level of an SPDX license expression, e.g. CopyleftStrong for "GPL-3.0-or-later".
+//
+// The least restrictive alternative of an "OR" expression is used, and the most restrictive part of an "AND"
+// expression. A license exception (with "WITH") reduces strong copyleft to weak, as it usually allows linking.
+// Expressions that cannot be parsed are treated as one license name.
+func Copyleft(expression string) string {
+	level, err := parseExpression(expression)
+	if err != nil {
+		level = copyleftLevel(strings.ReplaceAll(expression, " ", ""))
+	}
+	return levelNames[level]
+}
+
+// IsValidExpression checks the syntax of an SPDX license expression, e.g. "(MIT OR Apache-2.0) AND BSD-3-Clause".
+// It does not check that the license identifiers are known.
+func IsValidExpression(expression string) bool {
+	_, err := parseExpression(expression)
+	return err == nil
+}
+
+var levelNames = []string{CopyleftNone, CopyleftWeak, CopyleftStrong}
+
+func copyleftLevel(id string) int {
+	id = strings.ToUpper(id)
+	for _, p := range strongCopyleftPrefixes {
+		if strings.HasPrefix(id, p) {
+			return 2
+		}
+	}
+	for _, p := range weakCopyleftPrefixes {
+		if strings.HasPrefix(id, p) {
+			return 1
+		}
+	}
+	return 0
+}
+
+var (
+	expressionTokenPattern = regexp.MustCompile(`\(|\)|[^\s()]+`)
+	licenseIDPattern       = regexp.MustCompile(`^(DocumentRef-[A-Za-z0-9.\-]+:)?[A-Za-z0-9.\-]+\+?$`)
+)
+
+// expressionParser parses an SPDX license expression, evaluating its copyleft level.
+// See: https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpression(expression string) (int, error) {
+	p := &expressionParser{tokens: expressionTokenPattern.FindAllString(expression, -1)}
+	if len(p.tokens) == 0 {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidExpression)
+	}
+	level, err := p.parseOr()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.tokens) {
+		return 0, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidExpression, p.tokens[p.pos], expression)
+	}
+	return level, nil
+}
+
+// accept consumes the next token if it is the given operator (case-insensitive, as written in some manifests).
+func (p *expressionParser) accept(operator string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], operator) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) parseOr() (int, error) {
+	level, err := p.parseAnd()
+	if err != nil {
+		return 0, err
+	}
+	for p.accept("OR") {
+		alternative, err := p.parseAnd()
+		if err != nil {
+			return 0, err
+		}
+		level = min(level, alternative)
+	}
+	return level, nil
+}
+
+func (p *expressionParser) parseAnd() (int, error) {
+	level, err := p.parseWith()
+	if err != nil {
+		return 0, err
+	}
+	for p.accept("AND") {
+		other, err := p.parseWith()
+		if err != nil {
+			return 0, err
+		}
+		level = max(level, other)
+	}
+	return level, nil
+}
+
+func (p *expressionParser) parseWith() (int, error) {
+	level, err := p.parseAtom()
+	if err != nil {
+		return 0, err
+	}
+	if p.accept("WITH") {
+		if _, err := p.parseID(); err != nil {
+			return 0, err
+		}
+		level = min(level, 1)
+	}
+	return level, nil
+}
+
+func (p *expressionParser) parseAtom() (int, error) {
+	if p.accept("(") {
+		level, err := p.parseOr()
+		if err != nil {
+			return 0, err
+		}
+		if !p.accept(")") {
+			return 0, fmt.Errorf("%w: missing closing parenthesis", ErrInvalidExpression)
+		}
+		return level, nil
+	}
+	id, err := p.parseID()
+	if err != nil {
+		return 0, err
+	}
+	return copyleftLevel(id), nil
+}
+
+func (p *expressionParser) parseID() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("%w: unexpected end", ErrInvalidExpression)
+	}
+	id := p.tokens[p.pos]
+	switch strings.ToUpper(id) {
+	case "AND", "OR", "WITH":
+		return "", fmt.Errorf("%w: unexpected operator %q", ErrInvalidExpression, id)
+	}
+	if !licenseIDPattern.MatchString(id) {
+		return "", fmt.Errorf("%w: invalid identifier %q", ErrInvalidExpression, id)
+	}
+	p.pos++
+	return id, nil
+}
@@ -0,0 +1,49 @@
+package license_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/upsun/whatsun/pkg/license"
+)
+
+func TestCopyleft(t *testing.T) {
+	cases := []struct {
+		expression string
+		copyleft   string
+	}{
+		{"MIT", license.CopyleftNone},
+		{"Apache-2.0", license.CopyleftNone},
+		{"GPL-3.0-or-later", license.CopyleftStrong},
+		{"GPL-2.0+", license.CopyleftStrong},
+		{"AGPL-3.0-only", license.CopyleftStrong},
+		{"LGPL-2.1-only", license.CopyleftWeak},
+		{"MPL-2.0", license.CopyleftWeak},
+		{"MIT OR GPL-3.0-only", license.CopyleftNone},
+		{"MIT AND GPL-3.0-only", license.CopyleftStrong},
+		{"(MIT OR LGPL-3.0-only) AND EPL-2.0", license.CopyleftWeak},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", license.CopyleftWeak},
+		{"LGPL-2.1-only or GPL-3.0-or-later", license.CopyleftWeak},
+		{"GPL v3", license.CopyleftStrong},
+		{"", license.CopyleftNone},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.copyleft, license.Copyleft(c.expression), c.expression)
+	}
+}
+
+func TestIsValidExpression(t *testing.T) {
+	for _, valid := range []string{
+		"MIT",
+		"(MIT OR Apache-2.0) AND BSD-3-Clause",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0",
+		"LicenseRef-Proprietary",
+		"GPL-2.0+",
+	} {
+		assert.True(t, license.IsValidExpression(valid), valid)
+	}
+	for _, invalid := range []string{"", "MIT OR", "(MIT", "GPL v3 or later", "SEE LICENSE IN LICENSE.txt", "MIT)"} {
+		assert.False(t, license.IsValidExpression(invalid), invalid)
+	}
+}
@@ -0,0 +1,157 @@
+// Package license identifies licenses, in license files (by matching them against templates of common licenses)
+// and in SPDX license expressions.
+package license
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+//go:embed templates/*.txt
+var templateFS embed.FS
+
+// FileNames are the base names of license files, in order of priority. They are matched case-insensitively, with or
+// without an extension such as ".md" or ".txt".
+var FileNames = []string{"LICENSE", "LICENCE", "COPYING.LESSER", "COPYING", "UNLICENSE"}
+
+// minConfidence is the proportion of a template that must be found in a text for it to match.
+const minConfidence = 0.85
+
+// File is a license file found in a directory.
+type File struct {
+	Path       string  // The path of the file.
+	ID         string  // The SPDX identifier of the license, or an empty string if it is not known.
+	Confidence float64 // The proportion of the license template found in the file, between 0 and 1.
+}
+
+// Detect finds and classifies the license file in a directory. It returns nil if there is no license file.
+func Detect(fsys fs.FS, dir string) (*File, error) {
+	filePath, err := FindFile(fsys, dir)
+	if err != nil || filePath == "" {
+		return nil, err
+	}
+	b, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return nil, err
+	}
+	id, confidence := Classify(b)
+	return &File{Path: filePath, ID: id, Confidence: confidence}, nil
+}
+
+// FindFile returns the path of the license file in a directory, or an empty string if there is none.
+func FindFile(fsys fs.FS, dir string) (string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	best, bestPriority := "", len(FileNames)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.ToUpper(entry.Name())
+		if ext := filepath.Ext(name); ext == ".MD" || ext == ".TXT" {
+			name = strings.TrimSuffix(name, ext)
+		}
+		if i := slices.Index(FileNames, name); i != -1 && i < bestPriority {
+			best, bestPriority = entry.Name(), i
+		}
+	}
+	if best == "" {
+		return "", nil
+	}
+	return filepath.Join(dir, best), nil
+}
+
+var spdxIdentifierPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\r\n*]+)`)
+
+// Classify identifies the license in a text, returning its SPDX identifier and the confidence of the match. An empty
+// identifier is returned if the text does not match any known license closely enough, with the best confidence.
+//
+// The text matches a license if it contains most of the license's template, ignoring case, punctuation and
+// whitespace. If several templates match, the most complete and then the longest is chosen: for example, the
+// BSD-3-Clause license contains the BSD-2-Clause license plus a clause. An "SPDX-License-Identifier" tag takes
+// precedence over the text.
+func Classify(text []byte) (id string, confidence float64) {
+	if m := spdxIdentifierPattern.FindSubmatch(text); m != nil {
+		return strings.TrimSpace(string(m[1])), 1
+	}
+
+	shingles := shingleSet(normalize(string(text)))
+	scores := make([]float64, len(loadTemplates()))
+	var best float64
+	for i, t := range loadTemplates() {
+		var found int
+		for _, s := range t.shingles {
+			if _, ok := shingles[s]; ok {
+				found++
+			}
+		}
+		scores[i] = float64(found) / float64(len(t.shingles))
+		best = max(best, scores[i])
+	}
+	if best < minConfidence {
+		return "", best
+	}
+	// Prefer a longer template if it matches almost as well.
+	var size int
+	for i, t := range loadTemplates() {
+		if scores[i] >= best-0.05 && len(t.shingles) > size {
+			id, confidence, size = t.id, scores[i], len(t.shingles)
+		}
+	}
+	return id, confidence
+}
+
+type template struct {
+	id       string
+	shingles []string
+}
+
+var loadTemplates = sync.OnceValue(func() []template {
+	entries, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	var templates []template
+	for _, entry := range entries {
+		b, err := templateFS.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		var shingles []string
+		for s := range shingleSet(normalize(string(b))) {
+			shingles = append(shingles, s)
+		}
+		templates = append(templates, template{id: strings.TrimSuffix(entry.Name(), ".txt"), shingles: shingles})
+	}
+	return templates
+})
+
+var nonWordPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalize returns the words of a text, in lower case, ignoring punctuation and spelling variants.
+func normalize(text string) []string {
+	text = strings.ToLower(text)
+	text = strings.NewReplacer("licence", "license", "copyright owner", "copyright holder", "&", " and ").Replace(text)
+	return strings.Fields(nonWordPattern.ReplaceAllString(text, " "))
+}
+
+// shingleSet returns the set of sequences of 3 consecutive words.
+func shingleSet(words []string) map[string]struct{} {
+	const size = 3
+	set := make(map[string]struct{})
+	for i := 0; i+size <= len(words); i++ {
+		set[strings.Join(words[i:i+size], " ")] = struct{}{}
+	}
+	return set
+}
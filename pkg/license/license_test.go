@@ -0,0 +1,79 @@
+package license_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/license"
+)
+
+func TestClassify(t *testing.T) {
+	for _, id := range []string{"AGPL-3.0-only", "BSD-2-Clause", "BSD-3-Clause", "GPL-3.0-only", "ISC", "MIT"} {
+		b, err := os.ReadFile("testdata/" + id)
+		require.NoError(t, err)
+		classified, confidence := license.Classify(b)
+		assert.Equal(t, id, classified)
+		assert.GreaterOrEqual(t, confidence, 0.85, id)
+	}
+
+	id, _ := license.Classify([]byte("All rights reserved. Do not copy."))
+	assert.Empty(t, id)
+
+	id, confidence := license.Classify([]byte("// SPDX-License-Identifier: MPL-2.0\n"))
+	assert.Equal(t, "MPL-2.0", id)
+	assert.Equal(t, 1.0, confidence)
+}
+
+func TestDetect(t *testing.T) {
+	mit, err := os.ReadFile("testdata/MIT")
+	require.NoError(t, err)
+	gpl, err := os.ReadFile("testdata/GPL-3.0-only")
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"License.md":         {Data: mit},
+		"COPYING":            {Data: gpl},
+		"sub/COPYING":        {Data: gpl},
+		"sub/LICENSES/MIT":   {Data: mit},
+		"other/README.md":    {Data: []byte("# Other")},
+		"other/LICENSE.html": {Data: mit},
+	}
+
+	f, err := license.Detect(fsys, ".")
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, "License.md", f.Path)
+	assert.Equal(t, "MIT", f.ID)
+
+	f, err = license.Detect(fsys, "sub")
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	assert.Equal(t, "sub/COPYING", f.Path)
+	assert.Equal(t, "GPL-3.0-only", f.ID)
+
+	f, err = license.Detect(fsys, "other")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+}
+
+func TestProject(t *testing.T) {
+	mit, err := os.ReadFile("testdata/MIT")
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"LICENSE":          {Data: mit},
+		"package.json":     {Data: []byte(`{"license": "Apache-2.0"}`)},
+		"app/package.json": {Data: []byte(`{"license": "Apache-2.0"}`)},
+		"lib/README.md":    {Data: []byte("# Library")},
+	}
+
+	for dir, expected := range map[string]string{".": "MIT", "app": "Apache-2.0", "lib": ""} {
+		l, err := license.Project(fsys, dir)
+		require.NoError(t, err)
+		assert.Equal(t, expected, l, dir)
+	}
+}
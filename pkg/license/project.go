@@ -0,0 +1,40 @@
+package license
+
+import (
+	"io/fs"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/session"
+)
+
+// Project returns the license of the project in a directory: the license identified from its license file, or else
+// the license declared in a package manifest, such as package.json. It returns an empty string if it is not known.
+func Project(fsys fs.FS, dir string) (string, error) {
+	return ProjectSession(session.New(fsys), dir)
+}
+
+// ProjectSession is like Project, but reads files and dependencies through a session, reusing its caches.
+func ProjectSession(s *session.Session, dir string) (string, error) {
+	f, err := Detect(s.FS(), dir)
+	if err != nil {
+		return "", err
+	}
+	if f != nil && f.ID != "" {
+		return f.ID, nil
+	}
+	for _, managerType := range dep.ManagerTypes() {
+		m, err := s.Manager(managerType, dir)
+		if err != nil {
+			// Invalid manifests are ignored here: they are reported elsewhere.
+			continue
+		}
+		lm, ok := m.(dep.LicenseManager)
+		if !ok {
+			continue
+		}
+		if l := lm.License(); l != "" {
+			return l, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,95 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// cvss3Weights are the metric values of the CVSS v3 base score.
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore calculates the base score of a CVSS v3 vector, e.g.
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H" (with a score of 9.8).
+// See: https://www.first.org/cvss/v3.1/specification-document
+func CVSS3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, fmt.Errorf("not a CVSS v3 vector: %s", vector)
+	}
+	values := make(map[string]float64, len(cvss3Weights))
+	var scopeChanged bool
+	for _, part := range parts[1:] {
+		metric, value, _ := strings.Cut(part, ":")
+		if metric == "S" {
+			scopeChanged = value == "C"
+			continue
+		}
+		if weights, ok := cvss3Weights[metric]; ok {
+			w, ok := weights[value]
+			if !ok {
+				return 0, fmt.Errorf("invalid CVSS metric value %s in vector: %s", part, vector)
+			}
+			values[metric] = w
+		}
+	}
+	if len(values) != len(cvss3Weights) {
+		return 0, fmt.Errorf("incomplete CVSS vector: %s", vector)
+	}
+	if scopeChanged {
+		// Privileges have a greater impact if the scope is changed.
+		switch values["PR"] {
+		case cvss3Weights["PR"]["L"]:
+			values["PR"] = 0.68
+		case cvss3Weights["PR"]["H"]:
+			values["PR"] = 0.5
+		}
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp rounds up to one decimal place, avoiding floating point errors as in the CVSS v3.1 specification.
+func roundUp(f float64) float64 {
+	i := math.Round(f * 100000)
+	if math.Mod(i, 10000) == 0 {
+		return i / 100000
+	}
+	return (math.Floor(i/10000) + 1) / 10
+}
+
+// CVSSRating returns the qualitative severity rating of a CVSS score, e.g. "HIGH".
+func CVSSRating(score float64) string {
+	switch {
+	case score == 0:
+		return "NONE"
+	case score < 4:
+		return "LOW"
+	case score < 7:
+		return "MEDIUM"
+	case score < 9:
+		return "HIGH"
+	default:
+		return "CRITICAL"
+	}
+}
@@ -0,0 +1,353 @@
+// Package osv matches dependencies against a local database of advisories in the Open Source Vulnerability (OSV)
+// format, without network access.
+// See: https://ossf.github.io/osv-schema/
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/upsun/whatsun/pkg/dep"
+)
+
+// Advisory is an OSV advisory, with the fields needed for matching and reporting.
+type Advisory struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Withdrawn        string           `json:"withdrawn"`
+	Severity         []Severity       `json:"severity"`
+	Affected         []Affected       `json:"affected"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`  // e.g. "CVSS_V3"
+	Score string `json:"score"` // e.g. a CVSS vector
+}
+
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Severity          []Severity       `json:"severity"`
+	Ranges            []Range          `json:"ranges"`
+	Versions          []string         `json:"versions"`
+	DatabaseSpecific  DatabaseSpecific `json:"database_specific"`
+	EcosystemSpecific DatabaseSpecific `json:"ecosystem_specific"`
+}
+
+type Range struct {
+	Type   string  `json:"type"` // "SEMVER", "ECOSYSTEM" or "GIT"
+	Events []Event `json:"events"`
+}
+
+// Event is a version event in a range: only one of the fields is set.
+type Event struct {
+	Introduced   string `json:"introduced"`
+	Fixed        string `json:"fixed"`
+	LastAffected string `json:"last_affected"`
+	Limit        string `json:"limit"`
+}
+
+// DatabaseSpecific contains extra fields, of which only a severity label is used (e.g. in GitHub advisories).
+type DatabaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// Database is an in-memory index of advisories by ecosystem and package name.
+type Database struct {
+	advisories map[string][]*Advisory
+}
+
+// Load reads advisories from a directory, such as an extracted OSV data dump. JSON files are read individually, and
+// zip files (such as the "all.zip" file of each ecosystem) are read as archives of JSON files.
+func Load(fsys fs.FS) (*Database, error) {
+	db := &Database{advisories: make(map[string][]*Advisory)}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			b, err := fs.ReadFile(fsys, path)
+			if err != nil {
+				return err
+			}
+			return db.add(path, b)
+		case ".zip":
+			return db.addZip(fsys, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *Database) addZip(fsys fs.FS, path string) error {
+	b, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return err
+	}
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, f := range r.File {
+		if !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := db.add(path+"/"+f.Name, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *Database) add(path string, b []byte) error {
+	var a Advisory
+	if err := json.Unmarshal(b, &a); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if a.ID == "" || a.Withdrawn != "" {
+		return nil
+	}
+	for _, key := range a.keys() {
+		db.advisories[key] = append(db.advisories[key], &a)
+	}
+	return nil
+}
+
+// keys returns the index keys of the packages affected by an advisory, without duplicates.
+func (a *Advisory) keys() []string {
+	var keys []string
+	for _, affected := range a.Affected {
+		key := packageKey(affected.Package.Ecosystem, affected.Package.Name)
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Len returns the number of indexed packages.
+func (db *Database) Len() int {
+	return len(db.advisories)
+}
+
+func packageKey(ecosystem, name string) string {
+	switch ecosystem {
+	case "PyPI", "NuGet", "Packagist":
+		// Package names in these ecosystems are case-insensitive.
+		name = strings.ToLower(name)
+	}
+	if ecosystem == "PyPI" {
+		name = strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	}
+	return ecosystem + "/" + name
+}
+
+// Ecosystem returns the OSV ecosystem of a dependency found by a manager of the given type, or an empty string if
+// there is none.
+// See: https://ossf.github.io/osv-schema/#defined-ecosystems
+func Ecosystem(managerType string, d dep.Dependency) string {
+	switch managerType {
+	case dep.ManagerTypeJavaScript:
+		if d.ToolName != "meteor" && d.ToolName != "deno" {
+			return "npm"
+		}
+	case dep.ManagerTypePHP:
+		return "Packagist"
+	case dep.ManagerTypePython:
+		return "PyPI"
+	case dep.ManagerTypeRuby:
+		return "RubyGems"
+	case dep.ManagerTypeRust:
+		return "crates.io"
+	case dep.ManagerTypeGo:
+		return "Go"
+	case dep.ManagerTypeJava:
+		return "Maven"
+	case dep.ManagerTypeDotnet:
+		return "NuGet"
+	case dep.ManagerTypeElixir:
+		return "Hex"
+	case dep.ManagerTypeDart:
+		return "Pub"
+	case dep.ManagerTypeCpp:
+		if d.ToolName == "conan" {
+			return "ConanCenter"
+		}
+	case dep.ManagerTypeGitHubActions:
+		return "GitHub Actions"
+	}
+	return ""
+}
+
+// Match is an advisory affecting a dependency.
+type Match struct {
+	Advisory *Advisory
+	Severity string   // A severity label, e.g. "HIGH", if known
+	Fixed    []string // The versions in which the advisory is fixed, if any
+}
+
+// Query returns the advisories affecting a version of a package, sorted by ID.
+func (db *Database) Query(ecosystem, name, version string) []Match {
+	var matches []Match
+	key := packageKey(ecosystem, name)
+	for _, a := range db.advisories[key] {
+		var fixed []string
+		var affected bool
+		var severity string
+		for _, aff := range a.Affected {
+			if packageKey(aff.Package.Ecosystem, aff.Package.Name) != key || !aff.affects(version) {
+				continue
+			}
+			affected = true
+			for _, r := range aff.Ranges {
+				for _, e := range r.Events {
+					if e.Fixed != "" && !slices.Contains(fixed, e.Fixed) {
+						fixed = append(fixed, e.Fixed)
+					}
+				}
+			}
+			if severity == "" {
+				severity = aff.severity()
+			}
+		}
+		if !affected {
+			continue
+		}
+		if severity == "" {
+			severity = severityLabel(a.DatabaseSpecific.Severity, a.Severity)
+		}
+		matches = append(matches, Match{Advisory: a, Severity: severity, Fixed: fixed})
+	}
+	slices.SortFunc(matches, func(a, b Match) int {
+		return strings.Compare(a.Advisory.ID, b.Advisory.ID)
+	})
+	return matches
+}
+
+func (aff Affected) severity() string {
+	if aff.EcosystemSpecific.Severity != "" {
+		return severityLabel(aff.EcosystemSpecific.Severity, aff.Severity)
+	}
+	return severityLabel(aff.DatabaseSpecific.Severity, aff.Severity)
+}
+
+// severityLabel returns a severity label, computing it from a CVSS vector if needed.
+func severityLabel(label string, severities []Severity) string {
+	if label != "" {
+		return strings.ToUpper(label)
+	}
+	for _, s := range severities {
+		if s.Type == "CVSS_V3" {
+			if score, err := CVSS3BaseScore(s.Score); err == nil {
+				return CVSSRating(score)
+			}
+		}
+	}
+	return ""
+}
+
+// affects checks if a version is affected, either by being listed explicitly or by being in a range.
+// Git commit ranges are not supported, and ranges are not checked for versions that cannot be parsed.
+func (aff Affected) affects(version string) bool {
+	for _, v := range aff.Versions {
+		if v == version {
+			return true
+		}
+		if c, err := dep.CompareVersions(v, version); err == nil && c == 0 {
+			return true
+		}
+	}
+	if _, err := dep.CompareVersions(version, version); err != nil {
+		return false
+	}
+	for _, r := range aff.Ranges {
+		if r.Type != "GIT" && r.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// affects evaluates the events of a range, in version order: a version is affected if it is at or above an
+// "introduced" event, and not at or above a later "fixed" event (or above a later "last_affected" event).
+// Events with versions that cannot be compared are ignored.
+func (r Range) affects(version string) bool {
+	type event struct {
+		Event
+		version string
+	}
+	var events []event
+	for _, e := range r.Events {
+		v := e.Introduced + e.Fixed + e.LastAffected + e.Limit
+		if _, err := dep.CompareVersions(v, version); err == nil || e.Introduced == "0" {
+			events = append(events, event{e, v})
+		}
+	}
+	slices.SortStableFunc(events, func(a, b event) int {
+		// An "introduced" event of "0" sorts first.
+		if aZero, bZero := a.Introduced == "0", b.Introduced == "0"; aZero || bZero {
+			return cmp.Compare(boolInt(bZero), boolInt(aZero))
+		}
+		c, _ := dep.CompareVersions(a.version, b.version)
+		return c
+	})
+
+	var affected bool
+	for _, e := range events {
+		var c int
+		if e.Introduced != "0" {
+			c, _ = dep.CompareVersions(e.version, version)
+		}
+		switch {
+		case e.Introduced != "":
+			if c <= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if c <= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if c < 0 {
+				affected = false
+			}
+		case e.Limit != "":
+			if e.Limit != "*" && c <= 0 {
+				return false
+			}
+		}
+	}
+	return affected
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,157 @@
+package osv_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/osv"
+)
+
+const lodashAdvisory = `{
+	"id": "GHSA-35jh-r3h4-6jhm",
+	"aliases": ["CVE-2021-23337"],
+	"summary": "Command Injection in lodash",
+	"affected": [{
+		"package": {"ecosystem": "npm", "name": "lodash"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}],
+		"database_specific": {"severity": "HIGH"}
+	}]
+}`
+
+const djangoAdvisory = `{
+	"id": "PYSEC-2024-1",
+	"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+	"affected": [{
+		"package": {"ecosystem": "PyPI", "name": "Django"},
+		"ranges": [{"type": "ECOSYSTEM", "events": [
+			{"introduced": "5.0"}, {"fixed": "5.0.2"},
+			{"introduced": "4.2"}, {"fixed": "4.2.10"}
+		]}],
+		"versions": ["3.2.23"]
+	}]
+}`
+
+const withdrawnAdvisory = `{
+	"id": "GHSA-withdrawn",
+	"withdrawn": "2024-01-01T00:00:00Z",
+	"affected": [{
+		"package": {"ecosystem": "npm", "name": "lodash"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+	}]
+}`
+
+const serdeAdvisory = `{
+	"id": "RUSTSEC-2024-0001",
+	"affected": [{
+		"package": {"ecosystem": "crates.io", "name": "serde"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "1.0.0"}, {"last_affected": "1.0.100"}]}]
+	}]
+}`
+
+func zipFile(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDatabase(t *testing.T) {
+	fsys := fstest.MapFS{
+		"npm/GHSA-35jh-r3h4-6jhm.json":     {Data: []byte(lodashAdvisory)},
+		"npm/GHSA-withdrawn.json":          {Data: []byte(withdrawnAdvisory)},
+		"PyPI/all.zip":                     {Data: zipFile(t, map[string]string{"PYSEC-2024-1.json": djangoAdvisory})},
+		"crates.io/RUSTSEC-2024-0001.json": {Data: []byte(serdeAdvisory)},
+		"README.md":                        {Data: []byte("# Advisories")},
+	}
+	db, err := osv.Load(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, 3, db.Len())
+
+	cases := []struct {
+		ecosystem, name, version string
+		ids                      []string
+	}{
+		{"npm", "lodash", "4.17.20", []string{"GHSA-35jh-r3h4-6jhm"}},
+		{"npm", "lodash", "4.17.21", nil},
+		{"npm", "lodash", "latest", nil},
+		{"npm", "underscore", "1.0.0", nil},
+		{"PyPI", "django", "5.0.1", []string{"PYSEC-2024-1"}},
+		{"PyPI", "Django", "4.2.9", []string{"PYSEC-2024-1"}},
+		{"PyPI", "django", "4.2.10", nil},
+		{"PyPI", "django", "4.3", nil},
+		{"PyPI", "django", "3.2.23", []string{"PYSEC-2024-1"}},
+		{"PyPI", "django", "3.2.24", nil},
+		{"crates.io", "serde", "1.0.100", []string{"RUSTSEC-2024-0001"}},
+		{"crates.io", "serde", "1.0.101", nil},
+		{"crates.io", "serde", "0.9.0", nil},
+	}
+	for _, c := range cases {
+		var ids []string
+		for _, m := range db.Query(c.ecosystem, c.name, c.version) {
+			ids = append(ids, m.Advisory.ID)
+		}
+		assert.Equal(t, c.ids, ids, "%s %s %s", c.ecosystem, c.name, c.version)
+	}
+
+	matches := db.Query("npm", "lodash", "4.17.20")
+	require.Len(t, matches, 1)
+	assert.Equal(t, "HIGH", matches[0].Severity)
+	assert.Equal(t, []string{"4.17.21"}, matches[0].Fixed)
+
+	matches = db.Query("PyPI", "django", "5.0.1")
+	require.Len(t, matches, 1)
+	assert.Equal(t, "CRITICAL", matches[0].Severity)
+	assert.Equal(t, []string{"5.0.2", "4.2.10"}, matches[0].Fixed)
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	_, err := osv.Load(fstest.MapFS{"npm/broken.json": {Data: []byte("{")}})
+	assert.ErrorContains(t, err, "npm/broken.json")
+}
+
+func TestEcosystem(t *testing.T) {
+	assert.Equal(t, "npm", osv.Ecosystem(dep.ManagerTypeJavaScript, dep.Dependency{ToolName: "npm"}))
+	assert.Equal(t, "", osv.Ecosystem(dep.ManagerTypeJavaScript, dep.Dependency{ToolName: "deno"}))
+	assert.Equal(t, "Packagist", osv.Ecosystem(dep.ManagerTypePHP, dep.Dependency{}))
+	assert.Equal(t, "ConanCenter", osv.Ecosystem(dep.ManagerTypeCpp, dep.Dependency{ToolName: "conan"}))
+	assert.Equal(t, "", osv.Ecosystem(dep.ManagerTypeCpp, dep.Dependency{ToolName: "vcpkg"}))
+	assert.Equal(t, "", osv.Ecosystem(dep.ManagerTypeDocker, dep.Dependency{}))
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	cases := []struct {
+		vector string
+		score  float64
+		rating string
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, "CRITICAL"},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, "MEDIUM"},
+		{"CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", 9.9, "CRITICAL"},
+		{"CVSS:3.1/AV:L/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.8, "LOW"},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9, "MEDIUM"},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, "NONE"},
+	}
+	for _, c := range cases {
+		score, err := osv.CVSS3BaseScore(c.vector)
+		require.NoError(t, err)
+		assert.Equal(t, c.score, score, c.vector)
+		assert.Equal(t, c.rating, osv.CVSSRating(score), c.vector)
+	}
+
+	_, err := osv.CVSS3BaseScore("CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P")
+	assert.Error(t, err)
+	_, err = osv.CVSS3BaseScore("CVSS:3.1/AV:N/AC:L")
+	assert.Error(t, err)
+}
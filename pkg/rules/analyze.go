@@ -19,7 +19,7 @@ import (
 	"github.com/upsun/whatsun/internal/fsgitignore"
 	"github.com/upsun/whatsun/pkg/eval"
 	"github.com/upsun/whatsun/pkg/eval/celfuncs"
-	"github.com/upsun/whatsun/pkg/searchfs"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 type AnalyzerConfig struct {
@@ -62,9 +62,15 @@ func NewAnalyzer(rulesets []RulesetSpec, cnf *AnalyzerConfig) (*Analyzer, error)
 	return &Analyzer{evaluator: ev, rulesets: rulesets, cnf: cnf}, nil
 }
 
+// Analyze applies the rulesets to the directories in fsys, starting at root. Files and dependencies are read through
+// a new session, so the caches only last for this call.
 func (a *Analyzer) Analyze(ctx context.Context, fsys fs.FS, root string) ([]Report, error) {
-	fsys = searchfs.New(fsys)
+	return a.AnalyzeSession(ctx, session.New(fsys), root)
+}
 
+// AnalyzeSession applies the rulesets to the directories in the filesystem of a session, starting at root. Files and
+// dependencies are read through the session, so its caches can be reused afterward, e.g. to list dependencies.
+func (a *Analyzer) AnalyzeSession(ctx context.Context, sess *session.Session, root string) ([]Report, error) {
 	var (
 		// Limit the number of per-directory workers to 2 less than GOMAXPROCS.
 		numWorkers = max(1, runtime.GOMAXPROCS(0)-2)
@@ -73,7 +79,7 @@ func (a *Analyzer) Analyze(ctx context.Context, fsys fs.FS, root string) ([]Repo
 	)
 	errGroup.Go(func() error {
 		defer close(dirChan)
-		return a.collectDirectories(ctx, fsys, root, dirChan)
+		return a.collectDirectories(ctx, sess.FS(), root, dirChan)
 	})
 
 	var reportsChan = make(chan []Report, numWorkers)
@@ -89,7 +95,7 @@ func (a *Analyzer) Analyze(ctx context.Context, fsys fs.FS, root string) ([]Repo
 				default: // Continue only if the context was not canceled.
 				}
 				for _, ruleset := range a.rulesets {
-					subReports, err := a.applyRuleset(ruleset, fsys, path)
+					subReports, err := a.applyRuleset(ruleset, sess, path)
 					if err != nil {
 						return err
 					}
@@ -191,8 +197,8 @@ func (a *Analyzer) evalFuncForDirectory(dir string, celInput map[string]any) fun
 	}
 }
 
-func (a *Analyzer) applyRuleset(rs RulesetSpec, fsys fs.FS, path string) ([]Report, error) {
-	var celInput = celfuncs.FilesystemInput(fsys, path)
+func (a *Analyzer) applyRuleset(rs RulesetSpec, sess *session.Session, path string) ([]Report, error) {
+	var celInput = celfuncs.SessionInput(sess, path)
 
 	matches, err := FindMatches(rs.GetRules(), a.evalFuncForDirectory(path, celInput))
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/upsun/whatsun"
 	"github.com/upsun/whatsun/pkg/rules"
+	"github.com/upsun/whatsun/pkg/session"
 )
 
 var (
@@ -180,9 +181,123 @@ func TestAnalyze_TestFS_ActualRules(t *testing.T) {
 			Rules:     []string{"npm-lockfile"}, Groups: []string{"js"}},
 		{Ruleset: "package_managers", Path: "python", Result: "uv",
 			Rules: []string{"uv"}, Groups: []string{"python"}},
+		{Ruleset: "runtimes", Path: ".", Result: "php", Rules: []string{"php"},
+			With: map[string]rules.ReportValue{"version": {Value: "8.3"}}},
+		{Ruleset: "runtimes", Path: "python", Result: "python", Rules: []string{"python"},
+			With: map[string]rules.ReportValue{"version": {Value: "3.11"}}},
 	}, reports)
 }
 
+// analyzeRuleset analyzes a filesystem with the real rulesets, returning only the reports from one ruleset.
+func analyzeRuleset(t *testing.T, fsys fs.FS, ruleset string) []rules.Report {
+	analyzer := setupAnalyzerWithEmbeddedConfig(t, nil)
+	reports, err := analyzer.Analyze(t.Context(), fsys, ".")
+	require.NoError(t, err)
+	return slices.DeleteFunc(reports, func(r rules.Report) bool { return r.Ruleset != ruleset })
+}
+
+func TestAnalyze_Infrastructure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"registry/main.tf": &fstest.MapFile{Data: []byte(`module "vpc" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.4.0"
+}`)},
+		"private-registry/main.tf": &fstest.MapFile{Data: []byte(`module "net" {
+  source = "app.terraform.io/acme/network/aws"
+}`)},
+		"remote/main.tf": &fstest.MapFile{Data: []byte(`module "vpc" {
+  source = "git::https://github.com/acme/terraform-vpc.git?ref=v1.0.0"
+}
+
+module "dns" {
+  source = "github.com/acme/terraform-dns"
+}
+
+module "local" {
+  source = "./modules/local"
+}`)},
+	}
+
+	assert.Equal(t, []rules.Report{
+		{Ruleset: "infrastructure", Path: "private-registry", Result: "terraform", Rules: []string{"terraform"},
+			Groups: []string{"terraform"}, ReadFiles: []string{"providers.tf", "terraform.tf", "versions.tf"}},
+		{Ruleset: "infrastructure", Path: "private-registry", Result: "terraform-registry-modules",
+			Rules: []string{"terraform-registry-modules"}, Groups: []string{"terraform"}},
+		{Ruleset: "infrastructure", Path: "registry", Result: "terraform", Rules: []string{"terraform"},
+			Groups: []string{"terraform"}, ReadFiles: []string{"providers.tf", "terraform.tf", "versions.tf"}},
+		{Ruleset: "infrastructure", Path: "registry", Result: "terraform-registry-modules",
+			Rules: []string{"terraform-registry-modules"}, Groups: []string{"terraform"}},
+		{Ruleset: "infrastructure", Path: "remote", Result: "terraform", Rules: []string{"terraform"},
+			Groups: []string{"terraform"}, ReadFiles: []string{"providers.tf", "terraform.tf", "versions.tf"}},
+	}, analyzeRuleset(t, fsys, "infrastructure"))
+}
+
+func TestAnalyze_Services(t *testing.T) {
+	fsys := fstest.MapFS{
+		// Evidence from a driver dependency and an image, which are merged into one report.
+		"node/package.json": &fstest.MapFile{Data: []byte(`{"dependencies": {"pg": "^8.11"}}`)},
+		"node/compose.yaml": &fstest.MapFile{Data: []byte("services:\n  db:\n    image: postgres:16\n")},
+
+		"compose/docker-compose.yml": &fstest.MapFile{Data: []byte(`services:
+  db:
+    image: docker.io/library/mariadb:11
+  cache:
+    image: redis:7-alpine
+  search:
+    image: docker.elastic.co/elasticsearch/elasticsearch:8.15.0
+  queue:
+    image: rabbitmq:3-management
+`)},
+
+		"django/requirements.txt": &fstest.MapFile{Data: []byte("django==5.1\n")},
+		"django/mysite/settings.py": &fstest.MapFile{Data: []byte(`DATABASES = {
+    "default": {"ENGINE": "django.db.backends.postgresql", "NAME": "app"},
+}`)},
+
+		"rails/config/database.yml": &fstest.MapFile{Data: []byte(`default: &default
+  adapter: mysql2
+  pool: 5
+`)},
+
+		"laravel/.env.example":  &fstest.MapFile{Data: []byte("APP_NAME=Laravel\nDB_CONNECTION=pgsql\nDB_PORT=5432\n")},
+		"laravel/composer.json": &fstest.MapFile{Data: []byte(`{"require": {"php": ">=8.2", "ext-redis": "*"}}`)},
+	}
+
+	assert.Equal(t, []rules.Report{
+		{Ruleset: "services", Path: "compose", Result: "elasticsearch", Rules: []string{"elasticsearch-image"}},
+		{Ruleset: "services", Path: "compose", Result: "mysql", Rules: []string{"mysql-image"}},
+		{Ruleset: "services", Path: "compose", Result: "rabbitmq", Rules: []string{"rabbitmq-image"}},
+		{Ruleset: "services", Path: "compose", Result: "redis", Rules: []string{"redis-image"}},
+		{Ruleset: "services", Path: "django", Result: "postgresql", Rules: []string{"postgresql-django"}},
+		{Ruleset: "services", Path: "laravel", Result: "postgresql", Rules: []string{"postgresql-laravel"}},
+		{Ruleset: "services", Path: "laravel", Result: "redis", Rules: []string{"redis-driver"}},
+		{Ruleset: "services", Path: "node", Result: "postgresql",
+			Rules: []string{"postgresql-driver", "postgresql-image"}},
+		{Ruleset: "services", Path: "rails", Result: "mysql", Rules: []string{"mysql-rails"}},
+	}, analyzeRuleset(t, fsys, "services"))
+}
+
+// A malformed Compose file is reported as a diagnostic, and does not stop the analysis of the other evidence.
+func TestAnalyze_Services_MalformedCompose(t *testing.T) {
+	sess := session.New(fstest.MapFS{
+		"docker-compose.yml": &fstest.MapFile{Data: []byte("services:\n  db:\n    image: [postgres\n")},
+		"composer.json":      &fstest.MapFile{Data: []byte(`{"require": {"predis/predis": "^2.2"}}`)},
+	})
+
+	analyzer := setupAnalyzerWithEmbeddedConfig(t, nil)
+	reports, err := analyzer.AnalyzeSession(t.Context(), sess, ".")
+	require.NoError(t, err)
+
+	assert.Equal(t, []rules.Report{
+		{Ruleset: "services", Path: ".", Result: "redis", Rules: []string{"redis-driver"}},
+	}, slices.DeleteFunc(reports, func(r rules.Report) bool { return r.Ruleset != "services" }))
+
+	diagnostics := sess.Diagnostics()
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "docker-compose.yml", diagnostics[0].File)
+	assert.Contains(t, diagnostics[0].Message, "failed to parse as YAML")
+}
+
 // Benchmark analysis on the test filesystem, but with real rulesets.
 func BenchmarkAnalyze_TestFS_ActualRules(b *testing.B) {
 	analyzer := setupAnalyzerWithEmbeddedConfig(b, []string{"arg-ignore"})
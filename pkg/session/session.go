@@ -0,0 +1,103 @@
+// Package session holds the state of one analysis run over a filesystem: the caches of directory listings, file
+// contents and dependency managers. The caches are released along with the session, so that a long-running service
+// does not accumulate them across runs.
+package session
+
+import (
+	"bytes"
+	"cmp"
+	"io/fs"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/searchfs"
+)
+
+// Session caches data for the analysis of a filesystem. It is safe for concurrent use.
+type Session struct {
+	fsys     *cachingFS
+	managers sync.Map // of managerKey to *managerEntry
+}
+
+type managerKey struct {
+	managerType string
+	path        string
+}
+
+// managerEntry is a cached manager, which is created and initialized once.
+type managerEntry struct {
+	once    sync.Once
+	manager dep.Manager
+	err     error
+	done    atomic.Bool // Whether the manager has been initialized.
+}
+
+// New creates a session for analyzing a filesystem.
+func New(fsys fs.FS) *Session {
+	sfs, ok := fsys.(*searchfs.FS)
+	if !ok {
+		sfs = searchfs.New(fsys)
+	}
+	return &Session{fsys: &cachingFS{FS: sfs}}
+}
+
+// FS returns the filesystem, which caches directory listings and file contents.
+func (s *Session) FS() fs.FS {
+	return s.fsys
+}
+
+// Manager returns the initialized dependency manager of the given type for a directory, creating it on first use.
+// Concurrent callers wait for the manager to be initialized, and receive the same manager or error.
+func (s *Session) Manager(managerType, path string) (dep.Manager, error) {
+	v, _ := s.managers.LoadOrStore(managerKey{managerType, path}, &managerEntry{})
+	e := v.(*managerEntry) //nolint:errcheck // the type is known
+	e.once.Do(func() {
+		e.manager, e.err = dep.GetManager(managerType, s.fsys, path)
+		if e.err == nil {
+			e.err = e.manager.Init()
+		}
+		e.done.Store(true)
+	})
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.manager, nil
+}
+
+// Diagnostics returns the problems found in dependency files, such as syntax errors, by the managers that have been
+// initialized so far. They are sorted by file and line.
+func (s *Session) Diagnostics() []dep.Diagnostic {
+	var diagnostics []dep.Diagnostic
+	s.managers.Range(func(_, v any) bool {
+		e := v.(*managerEntry) //nolint:errcheck // the type is known
+		if e.done.Load() && e.err == nil {
+			diagnostics = append(diagnostics, e.manager.Diagnostics()...)
+		}
+		return true
+	})
+	slices.SortFunc(diagnostics, func(a, b dep.Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return diagnostics
+}
+
+// cachingFS adds a cache of file contents to a searchfs.FS.
+type cachingFS struct {
+	*searchfs.FS
+	contents sync.Map // of filename to []byte
+}
+
+// ReadFile implements fs.ReadFileFS. It returns a copy of the cached content, which the caller may modify.
+func (c *cachingFS) ReadFile(name string) ([]byte, error) {
+	if b, ok := c.contents.Load(name); ok {
+		return bytes.Clone(b.([]byte)), nil //nolint:errcheck // the type is known
+	}
+	b, err := fs.ReadFile(c.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	c.contents.Store(name, b)
+	return bytes.Clone(b), nil
+}
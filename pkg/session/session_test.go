@@ -0,0 +1,116 @@
+package session_test
+
+import (
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/upsun/whatsun/pkg/dep"
+	"github.com/upsun/whatsun/pkg/session"
+)
+
+// countingFS counts the times a file is opened in a filesystem.
+type countingFS struct {
+	fs.FS
+	name  string
+	opens atomic.Int32
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	if name == c.name {
+		c.opens.Add(1)
+	}
+	return c.FS.Open(name)
+}
+
+func TestSession_Manager(t *testing.T) {
+	s := session.New(fstest.MapFS{
+		"composer.json": {Data: []byte(`{"require": {"symfony/console": "^7.2"}}`)},
+	})
+
+	var wg sync.WaitGroup
+	managers := make([]dep.Manager, 10)
+	for i := range managers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := s.Manager(dep.ManagerTypePHP, ".")
+			assert.NoError(t, err)
+			managers[i] = m
+		}()
+	}
+	wg.Wait()
+
+	for _, m := range managers {
+		assert.Same(t, managers[0], m)
+	}
+	_, ok := managers[0].Get("symfony/console")
+	assert.True(t, ok)
+
+	_, err := s.Manager("nonexistent", ".")
+	assert.ErrorContains(t, err, "not supported")
+	_, err2 := s.Manager("nonexistent", ".")
+	assert.Equal(t, err, err2)
+}
+
+func TestSession_Isolation(t *testing.T) {
+	s1 := session.New(fstest.MapFS{"composer.json": {Data: []byte(`{"require": {"a/a": "^1"}}`)}})
+	s2 := session.New(fstest.MapFS{"composer.json": {Data: []byte(`{"require": {"b/b": "^1"}}`)}})
+
+	m1, err := s1.Manager(dep.ManagerTypePHP, ".")
+	require.NoError(t, err)
+	m2, err := s2.Manager(dep.ManagerTypePHP, ".")
+	require.NoError(t, err)
+
+	_, ok := m1.Get("a/a")
+	assert.True(t, ok)
+	_, ok = m2.Get("a/a")
+	assert.False(t, ok)
+	_, ok = m2.Get("b/b")
+	assert.True(t, ok)
+}
+
+func TestSession_Diagnostics(t *testing.T) {
+	s := session.New(fstest.MapFS{
+		"b/composer.json":     {Data: []byte("{\n  \"require\": {\"a/a\": \"^1\",}\n}")},
+		"a/package.json":      {Data: []byte(`{"dependencies": {"a": "^1"}}`)},
+		"a/package-lock.json": {Data: []byte(`{"packages": [`)},
+	})
+	assert.Empty(t, s.Diagnostics())
+
+	_, err := s.Manager(dep.ManagerTypePHP, "b")
+	require.NoError(t, err)
+	m, err := s.Manager(dep.ManagerTypeJavaScript, "a")
+	require.NoError(t, err)
+	_, ok := m.Get("a")
+	assert.True(t, ok)
+
+	diagnostics := s.Diagnostics()
+	require.Len(t, diagnostics, 2)
+	assert.Equal(t, "a/package-lock.json", diagnostics[0].File)
+	assert.Equal(t, "b/composer.json", diagnostics[1].File)
+	assert.Equal(t, 2, diagnostics[1].Line)
+}
+
+func TestSession_ReadFile(t *testing.T) {
+	base := &countingFS{FS: fstest.MapFS{"foo.txt": {Data: []byte("foo")}}, name: "foo.txt"}
+	s := session.New(base)
+
+	b, err := fs.ReadFile(s.FS(), "foo.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+	b[0] = 'g'
+
+	b, err = fs.ReadFile(s.FS(), "foo.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+	assert.EqualValues(t, 1, base.opens.Load())
+
+	_, err = fs.ReadFile(s.FS(), "bar.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}